@@ -0,0 +1,141 @@
+package csvx
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+var ErrInvalidArraySeparator = errors.New("invalid array separator")
+
+// splitArraySeparator splits the separator from array types like "string,array(|)",
+// which becomes "string,array" and "|".
+func splitArraySeparator(format string) (string, string, bool) {
+	idx := strings.LastIndex(format, ",array(")
+	if idx < 0 || !strings.HasSuffix(format, ")") {
+		return format, "", false
+	}
+
+	return format[:idx+len(",array")], format[idx+len(",array(") : len(format)-1], true
+}
+
+// withArraySeparator returns the format without separator and a parser that uses the separator of the format,
+// if the format declares one.
+func (c *CSVParser) withArraySeparator(format string) (*CSVParser, string, error) {
+	base, sep, ok := splitArraySeparator(format)
+	if !ok {
+		return c, format, nil
+	}
+
+	if utf8.RuneCountInString(sep) != 1 {
+		return nil, "", fmt.Errorf("%w: %q", ErrInvalidArraySeparator, sep)
+	}
+
+	parser := *c
+	parser.ArraySeparator, _ = utf8.DecodeRuneInString(sep)
+	return &parser, base, nil
+}
+
+// arraySeparator returns the rune that separates the elements of arrays, which is Comma by default.
+func (c *CSVParser) arraySeparator() rune {
+	if c.ArraySeparator != 0 {
+		return c.ArraySeparator
+	}
+	if c.Comma != 0 {
+		return c.Comma
+	}
+
+	return ','
+}
+
+// checkArraySeparator rejects separators that cannot separate csv values.
+func checkArraySeparator(sep rune) error {
+	if sep == '"' || sep == '\r' || sep == '\n' || sep == utf8.RuneError || !utf8.ValidRune(sep) {
+		return fmt.Errorf("%w: %q", ErrInvalidArraySeparator, sep)
+	}
+
+	return nil
+}
+
+// readArray splits the value of an array cell into its elements, which are separated by the array separator.
+// Elements that contain the separator, quotes or line breaks are quoted like csv values.
+//
+// For all array types, unquoted elements are trimmed and quoted elements are kept as they are.
+// Line breaks outside of quotes start a new record, empty lines are skipped.
+// Comments are not supported within arrays.
+func (c *CSVParser) readArray(value string) ([][]string, error) {
+	sep := c.arraySeparator()
+	if err := checkArraySeparator(sep); err != nil {
+		return nil, err
+	}
+
+	records := [][]string{}
+	record := []string{}
+	var elem strings.Builder
+	// quoted reports whether the element started with a quote, inQuotes whether the quote is still open
+	quoted, inQuotes, lineEmpty := false, false, true
+
+	endElem := func() {
+		if quoted {
+			record = append(record, elem.String())
+		} else {
+			record = append(record, strings.TrimSpace(elem.String()))
+		}
+		elem.Reset()
+		quoted = false
+	}
+	endRecord := func() {
+		if !lineEmpty {
+			endElem()
+			records = append(records, record)
+		}
+		record, lineEmpty = []string{}, true
+		elem.Reset()
+		quoted = false
+	}
+
+	for i := 0; i < len(value); {
+		r, size := utf8.DecodeRuneInString(value[i:])
+		i += size
+
+		switch {
+		case inQuotes:
+			if r != '"' {
+				elem.WriteRune(r)
+			} else if strings.HasPrefix(value[i:], `"`) {
+				// escaped quote
+				elem.WriteByte('"')
+				i++
+			} else {
+				inQuotes = false
+			}
+		case r == sep:
+			lineEmpty = false
+			endElem()
+		case r == '\r' && strings.HasPrefix(value[i:], "\n"):
+			// the line feed ends the record
+		case r == '\n':
+			endRecord()
+		case r == '"' && !quoted && strings.TrimSpace(elem.String()) == "":
+			// a quote after leading spaces starts a quoted element
+			lineEmpty = false
+			elem.Reset()
+			quoted, inQuotes = true, true
+		case quoted && (r == ' ' || r == '\t'):
+			// spaces between the closing quote and the separator
+		default:
+			// lazy quotes: other characters, including quotes within unquoted elements, are kept
+			lineEmpty = false
+			elem.WriteRune(r)
+		}
+	}
+	endRecord()
+
+	if len(records) == 0 {
+		// the value contains only line breaks
+		return [][]string{{}}, nil
+	}
+
+	return records, nil
+}
@@ -0,0 +1,174 @@
+package csvx
+
+import (
+	"errors"
+	"reflect"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestCSV_toTyped_arraySeparator(t *testing.T) {
+	type args struct {
+		value, format  string
+		isPointerType  bool
+		arraySeparator rune
+	}
+	tests := []struct {
+		name    string
+		args    args
+		want    interface{}
+		wantErr error
+	}{
+		{
+			name: "test_string_array_column_separator",
+			args: args{value: "a| b,c|\" d|e \"", format: "string,array(|)"},
+			want: []string{"a", "b,c", " d|e "},
+		},
+		{
+			name: "test_int64_array_column_separator",
+			args: args{value: "1; 2;3", format: "int64,array(;)"},
+			want: []int64{1, 2, 3},
+		},
+		{
+			name: "test_float64_array_parser_separator",
+			args: args{value: " 1.5 | 2", format: "float64,array", arraySeparator: '|'},
+			want: []float64{1.5, 2},
+		},
+		{
+			name: "test_bool_array_parser_separator",
+			args: args{value: "true |false", format: "bool,array", arraySeparator: '|'},
+			want: []bool{true, false},
+		},
+		{
+			name: "test_column_separator_overrides_parser",
+			args: args{value: "a|b", format: "string,array(;)", arraySeparator: '|'},
+			want: []string{"a|b"},
+		},
+		{
+			name: "test_string_array_quoted_spaces",
+			args: args{value: " a , \" b \" ", format: "string,array"},
+			want: []string{"a", " b "},
+		},
+		{
+			name: "test_string_array_escaped_quotes",
+			args: args{value: `"a ""b"", c",d"e`, format: "string,array"},
+			want: []string{`a "b", c`, `d"e`},
+		},
+		{
+			name: "test_int64_array_spaces",
+			args: args{value: " 1 | 2 ", format: "int64,array(|)"},
+			want: []int64{1, 2},
+		},
+		{
+			name:    "test_int64_array_quoted_spaces",
+			args:    args{value: `" 1"`, format: "int64,array"},
+			wantErr: strconv.ErrSyntax,
+		},
+		{
+			name: "test_custom_array_spaces",
+			args: args{value: " 1s , \"2s\"", format: "duration,array"},
+			want: []time.Duration{time.Second, 2 * time.Second},
+		},
+		{
+			name:    "test_multiple_lines",
+			args:    args{value: "a,b\nc", format: "string,array"},
+			wantErr: ErrOnlyOneRowIsAllowedForStringArray,
+		},
+		{
+			name: "test_quoted_line_break",
+			args: args{value: "\"a\nb\"\r\n\r\n", format: "string,array"},
+			want: []string{"a\nb"},
+		},
+		{
+			name: "test_string_array_comment_rune",
+			args: args{value: "#a,b", format: "string,array"},
+			want: []string{"#a", "b"},
+		},
+		{
+			name: "test_time_array_column_separator",
+			args: args{value: "1s|2s", format: "duration,array(|)", isPointerType: true},
+			want: &[]time.Duration{time.Second, 2 * time.Second},
+		},
+		{
+			name:    "test_invalid_separator",
+			args:    args{value: "a", format: "string,array(ab)"},
+			wantErr: ErrInvalidArraySeparator,
+		},
+		{
+			name:    "test_quote_separator",
+			args:    args{value: "a", format: "string,array(\")"},
+			wantErr: ErrInvalidArraySeparator,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			csv := CSVParser{Comma: ',', Comment: '#', ArraySeparator: tt.args.arraySeparator}
+
+			rslt, err := csv.toTyped(tt.args.value, tt.args.format, tt.args.isPointerType)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("TestToTypedArraySeparator() received error = %v, want %v", err, tt.wantErr)
+			}
+
+			if !reflect.DeepEqual(rslt, tt.want) {
+				t.Errorf("TestToTypedArraySeparator() is not equal. \ngot = %+#v\nwant = %+#v", rslt, tt.want)
+			}
+		})
+	}
+}
+
+func TestCSV_Typed_arraySeparator(t *testing.T) {
+	csv := New(WithComma(';'), WithArraySeparator(','))
+
+	rslt, err := csv.Typed([]byte("names;ids;flags\nstring,array;int64,array(|);bool,array\na,b;1|2;true,false"))
+	if err != nil {
+		t.Fatalf("TestTypedArraySeparator() received error = %v", err)
+	}
+
+	want := []map[string]interface{}{
+		{"names": []string{"a", "b"}, "ids": []int64{1, 2}, "flags": []bool{true, false}},
+	}
+	if !reflect.DeepEqual(rslt, want) {
+		t.Errorf("TestTypedArraySeparator() is not equal. \ngot = %+#v\nwant = %+#v", rslt, want)
+	}
+}
+
+func TestCSV_Marshal_arraySeparator(t *testing.T) {
+	type record struct {
+		Names  []string  `csv:"names"`
+		IDs    []int64   `csv:"ids,int64,array(|)"`
+		Scores []float64 `csv:"scores"`
+		Flags  []bool    `csv:"flags,bool,array(|)"`
+	}
+
+	data := []record{{
+		Names:  []string{"a;b", "c,d", `e"f`},
+		IDs:    []int64{1, 2},
+		Scores: []float64{1.5, 2},
+		Flags:  []bool{true, false},
+	}}
+
+	csv := New(WithComma(';'), WithArraySeparator(','))
+
+	encoded, err := csv.Marshal(data)
+	if err != nil {
+		t.Fatalf("TestMarshalArraySeparator() received error = %v", err)
+	}
+
+	want := "names;ids;scores;flags\n" +
+		"string,array;int64,array(|);float64,array;bool,array(|)\n" +
+		`"a;b,""c,d"",""e""""f""";1|2;1.5,2;true|false` + "\n"
+	if string(encoded) != want {
+		t.Errorf("TestMarshalArraySeparator() is not equal. \ngot = %s\nwant = %s", encoded, want)
+	}
+
+	var rslt []record
+	err = csv.Unmarshal(encoded, &rslt)
+	if err != nil {
+		t.Fatalf("TestMarshalArraySeparator() received error = %v", err)
+	}
+
+	if !reflect.DeepEqual(rslt, data) {
+		t.Errorf("TestMarshalArraySeparator() is not equal. \ngot = %+#v\nwant = %+#v", rslt, data)
+	}
+}
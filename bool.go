@@ -0,0 +1,48 @@
+package csvx
+
+import (
+	"strconv"
+	"strings"
+)
+
+// parseBool converts the value into a bool, which is used for the bool type and the elements of bool arrays.
+//
+// If neither TrueValues nor FalseValues are set, the values accepted by strconv.ParseBool are used.
+// Otherwise, only the configured values are accepted, ignoring case.
+// Values that are not recognised are rejected with a *strconv.NumError that wraps strconv.ErrSyntax.
+func (c *CSVParser) parseBool(value string) (bool, error) {
+	if c.TrueValues == nil && c.FalseValues == nil {
+		return strconv.ParseBool(value)
+	}
+
+	for _, token := range c.TrueValues {
+		if strings.EqualFold(value, token) {
+			return true, nil
+		}
+	}
+	for _, token := range c.FalseValues {
+		if strings.EqualFold(value, token) {
+			return false, nil
+		}
+	}
+
+	return false, &strconv.NumError{Func: "ParseBool", Num: value, Err: strconv.ErrSyntax}
+}
+
+// formatBool converts the value into the first of the configured true or false values,
+// or "true" and "false" if none are configured.
+func (c *CSVParser) formatBool(value bool) string {
+	if c.TrueValues == nil && c.FalseValues == nil {
+		return strconv.FormatBool(value)
+	}
+
+	values := c.FalseValues
+	if value {
+		values = c.TrueValues
+	}
+	if len(values) == 0 {
+		return ""
+	}
+
+	return values[0]
+}
@@ -0,0 +1,122 @@
+package csvx
+
+import (
+	"errors"
+	"reflect"
+	"strconv"
+	"testing"
+)
+
+func TestCSV_toTyped_bool(t *testing.T) {
+	type args struct {
+		value, format string
+		isPointerType bool
+		trueValues    []string
+		falseValues   []string
+	}
+	tests := []struct {
+		name    string
+		args    args
+		want    interface{}
+		wantErr error
+	}{
+		{
+			name: "test_bool_strconv",
+			args: args{value: "T", format: "bool"},
+			want: true,
+		},
+		{
+			name:    "test_bool_invalid",
+			args:    args{value: "yes", format: "bool"},
+			want:    false,
+			wantErr: strconv.ErrSyntax,
+		},
+		{
+			name: "test_bool_array_strconv",
+			args: args{value: "true, 0,,FALSE", format: "bool,array"},
+			want: []bool{true, false, false, false},
+		},
+		{
+			name:    "test_bool_array_invalid",
+			args:    args{value: "true,yes", format: "bool,array"},
+			wantErr: strconv.ErrSyntax,
+		},
+		{
+			name: "test_bool_vocabulary",
+			args: args{value: "Ja", format: "bool", trueValues: []string{"ja", "yes"}, falseValues: []string{"nein", "no"}},
+			want: true,
+		},
+		{
+			name:    "test_bool_vocabulary_replaces_strconv",
+			args:    args{value: "true", format: "bool", trueValues: []string{"ja"}, falseValues: []string{"nein"}},
+			want:    false,
+			wantErr: strconv.ErrSyntax,
+		},
+		{
+			name: "test_bool_array_vocabulary",
+			args: args{value: "yes, NO,", format: "bool,array", trueValues: []string{"yes"}, falseValues: []string{"no"}},
+			want: []bool{true, false, false},
+		},
+		{
+			name: "test_bool_cross",
+			args: args{value: "x,,x", format: "bool,array", trueValues: []string{"x"}, falseValues: []string{""}},
+			want: []bool{true, false, true},
+		},
+		{
+			name: "test_bool_numbers_ptr",
+			args: args{value: "0", format: "bool", isPointerType: true, trueValues: []string{"1"}, falseValues: []string{"0"}},
+			want: func(b bool) *bool { return &b }(false),
+		},
+		{
+			name: "test_bool_empty_ptr",
+			args: args{value: "", format: "bool", isPointerType: true, trueValues: []string{"1"}, falseValues: []string{"0"}},
+			want: nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			csv := CSVParser{Comma: ',', Comment: '#', TrueValues: tt.args.trueValues, FalseValues: tt.args.falseValues}
+
+			rslt, err := csv.toTyped(tt.args.value, tt.args.format, tt.args.isPointerType)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("TestToTypedBool() received error = %v, want %v", err, tt.wantErr)
+			}
+
+			if !reflect.DeepEqual(rslt, tt.want) {
+				t.Errorf("TestToTypedBool() is not equal. \ngot = %+#v\nwant = %+#v", rslt, tt.want)
+			}
+		})
+	}
+}
+
+func TestCSV_Marshal_bool(t *testing.T) {
+	type record struct {
+		Active bool   `csv:"active"`
+		Flags  []bool `csv:"flags"`
+	}
+
+	data := []record{{Active: true, Flags: []bool{true, false}}, {Active: false}}
+
+	csv := New(WithBoolValues([]string{"ja", "j"}, []string{"nein", "n"}))
+
+	encoded, err := csv.Marshal(data)
+	if err != nil {
+		t.Fatalf("TestMarshalBool() received error = %v", err)
+	}
+
+	want := "active,flags\nbool,\"bool,array\"\nja,\"ja,nein\"\nnein,\n"
+	if string(encoded) != want {
+		t.Errorf("TestMarshalBool() is not equal. \ngot = %q\nwant = %q", encoded, want)
+	}
+
+	var rslt []record
+	err = csv.Unmarshal(encoded, &rslt)
+	if err != nil {
+		t.Fatalf("TestMarshalBool() received error = %v", err)
+	}
+
+	data[1].Flags = []bool{}
+	if !reflect.DeepEqual(rslt, data) {
+		t.Errorf("TestMarshalBool() is not equal. \ngot = %+#v\nwant = %+#v", rslt, data)
+	}
+}
@@ -0,0 +1,302 @@
+package csvx
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"math/big"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+var (
+	ErrConstraintViolation = errors.New("constraint violation")
+	ErrInvalidConstraint   = errors.New("invalid constraint")
+)
+
+// Constraint defines the rules the values of a column must follow.
+//
+// Min and Max apply to numbers, MinLength and MaxLength to strings. Pattern and Enum apply to the text
+// of all values. For arrays, the rules apply to each element. Empty and null cells are only checked by Required.
+type Constraint struct {
+	// Required defines whether the cell must not be empty, null or missing.
+	Required bool
+	// Unique defines whether the values of the column must be different from each other.
+	Unique bool
+	// Min and Max define the range of numbers, if they are set.
+	Min *float64
+	Max *float64
+	// MinLength and MaxLength define the range of the number of characters of strings, if they are not 0.
+	MinLength int
+	MaxLength int
+	// Pattern defines a regular expression that the values must match, if it is set.
+	Pattern *regexp.Regexp
+	// Enum defines the allowed values, if it is set.
+	Enum []string
+}
+
+// ConstraintError describes a value that violates a constraint of its column.
+//
+// It is wrapped in a *ParseError and matches ErrConstraintViolation with errors.Is.
+type ConstraintError struct {
+	// Rule is the name of the violated constraint, like "required", "unique", "min", "max", "minlen",
+	// "maxlen", "regex" or "enum".
+	Rule string
+	// Message describes the violation.
+	Message string
+}
+
+// Error returns the violated rule and the description of the violation.
+func (e *ConstraintError) Error() string {
+	return fmt.Sprintf("%s: %s: %s", ErrConstraintViolation, e.Rule, e.Message)
+}
+
+// Is reports whether target is ErrConstraintViolation.
+func (e *ConstraintError) Is(target error) bool {
+	return target == ErrConstraintViolation
+}
+
+// parseConstraint parses the constraints of a column as declared in the constraint row,
+// like "required,unique,min=1,max=10,minlen=2,maxlen=20,regex=^[a-z]+$,enum=a|b|c".
+// The rules are always separated by ',', independent of Comma and ArraySeparator, and are quoted
+// like csv values, if they contain ','. The values of enum are separated by '|' within the rule.
+func parseConstraint(spec string) (*Constraint, error) {
+	r := csv.NewReader(strings.NewReader(spec))
+	r.Comma = ','
+	r.TrimLeadingSpace = true
+	r.FieldsPerRecord = -1
+	r.LazyQuotes = true
+
+	records, err := r.ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidConstraint, err)
+	}
+	if len(records) > 1 {
+		return nil, fmt.Errorf("%w: %q contains more than one line", ErrInvalidConstraint, spec)
+	}
+
+	cons := &Constraint{}
+	for _, record := range records {
+		for _, rule := range record {
+			rule = strings.TrimSpace(rule)
+			if rule == "" {
+				continue
+			}
+
+			if err := cons.parseRule(rule); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return cons, nil
+}
+
+// parseRule sets a single rule like "required" or "min=1".
+func (cons *Constraint) parseRule(rule string) error {
+	name, arg := rule, ""
+	if idx := strings.IndexByte(rule, '='); idx >= 0 {
+		name, arg = rule[:idx], rule[idx+1:]
+	}
+
+	var err error
+	switch name {
+	case "required":
+		cons.Required = true
+	case "unique":
+		cons.Unique = true
+	case "min", "max":
+		var f float64
+		f, err = strconv.ParseFloat(arg, 64)
+		if name == "min" {
+			cons.Min = &f
+		} else {
+			cons.Max = &f
+		}
+	case "minlen":
+		cons.MinLength, err = strconv.Atoi(arg)
+	case "maxlen":
+		cons.MaxLength, err = strconv.Atoi(arg)
+	case "regex":
+		cons.Pattern, err = regexp.Compile(arg)
+	case "enum":
+		cons.Enum = strings.Split(arg, "|")
+	default:
+		return fmt.Errorf("%w: unknown rule %q", ErrInvalidConstraint, rule)
+	}
+	if err != nil {
+		return fmt.Errorf("%w: %q: %s", ErrInvalidConstraint, rule, err)
+	}
+
+	return nil
+}
+
+// columnConstraint holds the constraint of a column and the values seen by a single decoder.
+type columnConstraint struct {
+	Constraint
+	// seen contains the line of each value, if the values must be unique.
+	seen map[string]int
+}
+
+// checkConstraints attaches the constraints of the constraint row or, for columns without constraint,
+// of the Constraints setting to the columns.
+func (c *CSVParser) checkConstraints(headerInfo map[int]field, specs []string) (map[int]field, error) {
+	for idx := 0; idx < len(headerInfo); idx++ {
+		hf := headerInfo[idx]
+		if c.isTyped && hf.Type == "" && c.SkipEmptyColumns {
+			continue
+		}
+
+		var cons *Constraint
+		if idx < len(specs) && specs[idx] != "" {
+			var err error
+			cons, err = parseConstraint(specs[idx])
+			if err != nil {
+				return nil, fmt.Errorf("%w in column %d", err, idx+1)
+			}
+		} else if schema, ok := c.Constraints[hf.Name]; ok {
+			cons = &schema
+		}
+
+		if cons != nil {
+			hf.constraint = &columnConstraint{Constraint: *cons, seen: map[string]int{}}
+			headerInfo[idx] = hf
+		}
+	}
+
+	return headerInfo, nil
+}
+
+// check checks the raw value of a cell and its converted value against the constraint.
+// empty reports whether the cell is empty, null or missing.
+func (cc *columnConstraint) check(raw string, value interface{}, empty bool, line int) error {
+	if cc == nil {
+		return nil
+	}
+
+	if empty {
+		if cc.Required {
+			return &ConstraintError{Rule: "required", Message: "value is required"}
+		}
+
+		return nil
+	}
+
+	if cc.Unique {
+		if first, ok := cc.seen[raw]; ok {
+			return &ConstraintError{Rule: "unique", Message: fmt.Sprintf("value is not unique, it is used in line %d already", first)}
+		}
+		cc.seen[raw] = line
+	}
+
+	for _, elem := range constraintElements(value) {
+		if err := cc.checkElement(elem); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// checkElement checks a single value, or an element of an array, against the constraint.
+func (cc *columnConstraint) checkElement(value interface{}) error {
+	if f, ok := constraintNumber(value); ok {
+		if cc.Min != nil && f < *cc.Min {
+			return &ConstraintError{Rule: "min", Message: fmt.Sprintf("%v is less than %v", value, *cc.Min)}
+		}
+		if cc.Max != nil && f > *cc.Max {
+			return &ConstraintError{Rule: "max", Message: fmt.Sprintf("%v is greater than %v", value, *cc.Max)}
+		}
+	}
+
+	text, isString := value.(string)
+	if !isString {
+		text = fmt.Sprint(value)
+	}
+
+	if isString {
+		length := utf8.RuneCountInString(text)
+		if cc.MinLength > 0 && length < cc.MinLength {
+			return &ConstraintError{Rule: "minlen", Message: fmt.Sprintf("%q is shorter than %d characters", text, cc.MinLength)}
+		}
+		if cc.MaxLength > 0 && length > cc.MaxLength {
+			return &ConstraintError{Rule: "maxlen", Message: fmt.Sprintf("%q is longer than %d characters", text, cc.MaxLength)}
+		}
+	}
+
+	if cc.Pattern != nil && !cc.Pattern.MatchString(text) {
+		return &ConstraintError{Rule: "regex", Message: fmt.Sprintf("%q does not match %q", text, cc.Pattern)}
+	}
+
+	if cc.Enum != nil {
+		for _, allowed := range cc.Enum {
+			if text == allowed {
+				return nil
+			}
+		}
+
+		return &ConstraintError{Rule: "enum", Message: fmt.Sprintf("%q is not one of %s", text, strings.Join(cc.Enum, "|"))}
+	}
+
+	return nil
+}
+
+// constraintElements returns the dereferenced value, or the elements of an array.
+func constraintElements(value interface{}) []interface{} {
+	switch value.(type) {
+	case *big.Int, *big.Rat, *big.Float:
+		return []interface{}{value}
+	}
+
+	rv := reflect.ValueOf(value)
+	for rv.Kind() == reflect.Ptr || rv.Kind() == reflect.Interface {
+		if rv.IsNil() {
+			return nil
+		}
+		rv = rv.Elem()
+	}
+	if !rv.IsValid() {
+		return nil
+	}
+
+	if rv.Kind() != reflect.Slice {
+		return []interface{}{rv.Interface()}
+	}
+
+	elems := []interface{}{}
+	for i := 0; i < rv.Len(); i++ {
+		elems = append(elems, constraintElements(rv.Index(i).Interface())...)
+	}
+
+	return elems
+}
+
+// constraintNumber converts numbers into float64 to compare them with Min and Max.
+func constraintNumber(value interface{}) (float64, bool) {
+	switch v := value.(type) {
+	case *big.Int:
+		f, _ := new(big.Float).SetInt(v).Float64()
+		return f, true
+	case *big.Rat:
+		f, _ := v.Float64()
+		return f, true
+	case *big.Float:
+		f, _ := v.Float64()
+		return f, true
+	}
+
+	rv := reflect.ValueOf(value)
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(rv.Int()), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return float64(rv.Uint()), true
+	case reflect.Float32, reflect.Float64:
+		return rv.Float(), true
+	default:
+		return 0, false
+	}
+}
@@ -0,0 +1,211 @@
+package csvx
+
+import (
+	"errors"
+	"reflect"
+	"regexp"
+	"testing"
+)
+
+func TestCSV_Validate_constraints(t *testing.T) {
+	type args struct {
+		data        []byte
+		constraints map[string]Constraint
+	}
+	tests := []struct {
+		name      string
+		args      args
+		want      []map[string]interface{}
+		wantRules []string
+		wantLines []int
+	}{
+		{
+			name: "test_constraint_row",
+			args: args{
+				data: []byte("id,name,score,status,tags\n" +
+					"int64,string,float64,string,\"string,array\"\n" +
+					"\"required,unique\",\"minlen=2,maxlen=5,regex=^[a-z]+$\",\"min=0,max=10\",enum=draft|published,maxlen=3\n" +
+					"1,abc,5,draft,\"a,b\"\n" +
+					"1,abc,5,draft,a\n" +
+					",abc,5,draft,a\n" +
+					"2,a,5,draft,a\n" +
+					"3,abcdef,5,draft,a\n" +
+					"4,ABC,5,draft,a\n" +
+					"5,abc,-1,draft,a\n" +
+					"6,abc,11,draft,a\n" +
+					"7,abc,5,drafts,a\n" +
+					"8,abc,5,draft,\"a,long\"\n" +
+					"9\n"),
+			},
+			want: []map[string]interface{}{
+				{"id": int64(1), "name": "abc", "score": float64(5), "status": "draft", "tags": []string{"a", "b"}},
+				{"id": int64(9)},
+			},
+			wantRules: []string{"unique", "required", "minlen", "maxlen", "regex", "min", "max", "enum", "maxlen"},
+			wantLines: []int{5, 6, 7, 8, 9, 10, 11, 12, 13},
+		},
+		{
+			name: "test_schema",
+			args: args{
+				data: []byte("id,code\nint,string\n1,ab\n2,\n3,AB\n"),
+				constraints: map[string]Constraint{
+					"code": {Required: true, Pattern: regexp.MustCompile("^[a-z]+$")},
+				},
+			},
+			want: []map[string]interface{}{
+				{"id": 1, "code": "ab"},
+			},
+			wantRules: []string{"required", "regex"},
+			wantLines: []int{4, 5},
+		},
+		{
+			name: "test_missing_required",
+			args: args{
+				data: []byte("id,code\nint,string\nrequired,required\n1\n\n"),
+			},
+			want:      []map[string]interface{}{},
+			wantRules: []string{"required"},
+			wantLines: []int{4},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			csv := New(WithConstraintRow(tt.args.constraints == nil), WithConstraints(tt.args.constraints))
+
+			rslt, err := csv.Validate(tt.args.data)
+
+			var errs ParseErrors
+			if !errors.As(err, &errs) {
+				t.Fatalf("TestValidateConstraints() received error = %v", err)
+			}
+
+			rules := []string{}
+			lines := []int{}
+			for _, e := range errs {
+				var consErr *ConstraintError
+				if !errors.As(e, &consErr) || !errors.Is(e, ErrConstraintViolation) {
+					t.Fatalf("TestValidateConstraints() received error = %v", e)
+				}
+
+				rules = append(rules, consErr.Rule)
+				lines = append(lines, e.Line)
+			}
+
+			if !reflect.DeepEqual(rules, tt.wantRules) || !reflect.DeepEqual(lines, tt.wantLines) {
+				t.Errorf("TestValidateConstraints() is not equal. \ngot = %v %v\nwant = %v %v", rules, lines, tt.wantRules, tt.wantLines)
+			}
+
+			if !reflect.DeepEqual(rslt, tt.want) {
+				t.Errorf("TestValidateConstraints() is not equal. \ngot = %+#v\nwant = %+#v", rslt, tt.want)
+			}
+		})
+	}
+}
+
+func TestCSV_Typed_constraints(t *testing.T) {
+	csv := New(WithConstraintRow(true))
+
+	_, err := csv.Typed([]byte("id\nint\nmin=1\n0\n"))
+
+	want := `line 4, column 1 ("id" of type "int"): invalid value "0": constraint violation: min: 0 is less than 1`
+	if err == nil || err.Error() != want {
+		t.Errorf("TestTypedConstraints() received error = %v, want %s", err, want)
+	}
+}
+
+func TestCSV_Typed_constraintSeparators(t *testing.T) {
+	tests := []struct {
+		name string
+		opts []Option
+		data []byte
+	}{
+		{
+			name: "test_semicolon",
+			opts: []Option{WithComma(';')},
+			data: []byte("id;status\nint;string\nrequired,min=1;enum=draft|published\n0;archived\n"),
+		},
+		{
+			name: "test_pipe_array_separator",
+			opts: []Option{WithArraySeparator('|')},
+			data: []byte("id,status\nint,string\n\"required,min=1\",enum=draft|published\n0,archived\n"),
+		},
+		{
+			name: "test_pipe_comma",
+			opts: []Option{WithComma('|')},
+			data: []byte("id|status\nint|string\nrequired,min=1|\"enum=draft|published\"\n0|archived\n"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			csv := New(append(tt.opts, WithConstraintRow(true))...)
+
+			_, err := csv.Validate(tt.data)
+
+			var errs ParseErrors
+			if !errors.As(err, &errs) || len(errs) != 2 {
+				t.Fatalf("TestTypedConstraintSeparators() received error = %v, want 2 violations", err)
+			}
+
+			for idx, rule := range []string{"min", "enum"} {
+				var consErr *ConstraintError
+				if !errors.As(errs[idx], &consErr) || consErr.Rule != rule {
+					t.Errorf("TestTypedConstraintSeparators() received error = %v, want rule %s", errs[idx], rule)
+				}
+			}
+		})
+	}
+}
+
+func TestCSV_parseConstraint(t *testing.T) {
+	one, ten := 1.0, 10.0
+
+	tests := []struct {
+		name    string
+		spec    string
+		want    *Constraint
+		wantErr error
+	}{
+		{
+			name: "test_rules",
+			spec: "required, unique,min=1,max=10,minlen=2,maxlen=3,enum=a|b",
+			want: &Constraint{Required: true, Unique: true, Min: &one, Max: &ten, MinLength: 2, MaxLength: 3, Enum: []string{"a", "b"}},
+		},
+		{
+			name: "test_quoted_regex",
+			spec: `"regex=^[a-z]{1,3}$"`,
+			want: &Constraint{Pattern: regexp.MustCompile("^[a-z]{1,3}$")},
+		},
+		{
+			name: "test_quoted_enum",
+			spec: `required,"enum=a,b|c"`,
+			want: &Constraint{Required: true, Enum: []string{"a,b", "c"}},
+		},
+		{
+			name:    "test_unknown_rule",
+			spec:    "positive",
+			wantErr: ErrInvalidConstraint,
+		},
+		{
+			name:    "test_invalid_number",
+			spec:    "min=one",
+			wantErr: ErrInvalidConstraint,
+		},
+		{
+			name:    "test_invalid_regex",
+			spec:    "regex=[",
+			wantErr: ErrInvalidConstraint,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rslt, err := parseConstraint(tt.spec)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("TestParseConstraint() received error = %v, want %v", err, tt.wantErr)
+			}
+
+			if !reflect.DeepEqual(rslt, tt.want) {
+				t.Errorf("TestParseConstraint() is not equal. \ngot = %+#v\nwant = %+#v", rslt, tt.want)
+			}
+		})
+	}
+}
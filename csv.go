@@ -6,9 +6,11 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
 	"reflect"
 	"strconv"
 	"strings"
+	"time"
 )
 
 var (
@@ -24,34 +26,104 @@ var (
 type field struct {
 	Name string
 	Type string
+	// Collect defines whether the values of all columns with this name are collected into a []interface{}.
+	Collect bool
+	// Default is the value of empty cells, if HasDefault is set.
+	Default    string
+	HasDefault bool
+	// constraint is checked for each cell of the column, if it is set.
+	constraint *columnConstraint
 }
 
+// CSVParser holds the settings for reading and writing csv.
+//
+// The parser does not modify its settings while reading or writing, so it is safe for concurrent use,
+// as long as the fields are not changed and RegisterType is not called at the same time.
+// Use New to create a parser with all settings applied at once.
 type CSVParser struct {
 	// Comma defines the rune with which the entries in the csv file are separated from each other.
 	Comma rune
 	// Comment defines the rune used to mark comment strings within the CSV.
-	// If the line starts with this rune, the whole line is ignored.
+	// If the line starts with this rune, the whole line is ignored. Quoted cells that start with it are values.
 	Comment rune
 	// TrimLeadingSpace specifies whether leading spaces should be trimmed or not.
 	TrimLeadingSpace bool
 	// SkipEmptyColumns defines whether empty rows should be ignored or not.
 	SkipEmptyColumns bool
+	// DuplicateHeaders defines how columns with the same header name are handled.
+	// By default, later columns overwrite earlier ones.
+	DuplicateHeaders DuplicateHeaderPolicy
+	// RejectEmptyHeaders defines whether blank header names are rejected with ErrEmptyHeader.
+	RejectEmptyHeaders bool
+	// NestedHeaders defines whether header names like "address.street" or "tags[0]" build nested maps and slices
+	// in the maps returned by Typed and Untyped, and whether Marshal flattens nested maps into such columns.
+	NestedHeaders bool
+	// StrictRecordWidth defines whether rows with more or fewer cells than the header are rejected with ErrRecordWidth.
+	StrictRecordWidth bool
+	// OverflowKey defines the name under which the surplus cells of a row are collected as []string.
+	// If it is not set, surplus cells are dropped.
+	OverflowKey string
+	// NullTokens defines the cell values that are read as null, like "NULL" or "\N".
+	// For all types, null cells are converted like empty cells, so they are nil for pointer types and json.
+	// If NullTokens is set, empty cells of *string columns are read as empty strings instead of nil,
+	// and nil values are written as the first null token.
+	// In untyped csv, null cells are nil.
+	NullTokens []string
+	// ConstraintRow defines whether the row after the header names, or after the type row for typed csv,
+	// declares the constraints of the columns, like "required,min=1".
+	ConstraintRow bool
+	// Constraints defines the constraints of the columns by header name.
+	// Constraints declared in the constraint row take precedence.
+	Constraints map[string]Constraint
+	// TrueValues and FalseValues define the values of bool cells, like "yes" and "no", ignoring case.
+	// If neither is set, the values accepted by strconv.ParseBool are used.
+	// Values that are not recognised are rejected, also within bool arrays.
+	TrueValues  []string
+	FalseValues []string
+	// ArraySeparator defines the rune with which the elements of array types are separated from each other.
+	// If it is not set, Comma is used. Single columns can declare their own separator, like "string,array(|)".
+	ArraySeparator rune
+	// DecimalSeparator and GroupSeparator define the number format of number cells, like ',' and '.' for "1.234,56".
+	// If DecimalSeparator is not set, '.' is used. If GroupSeparator is not set, numbers are not grouped.
+	// Grouping separators must separate groups of three digits and differ from the decimal separator,
+	// otherwise reading a number fails with ErrInvalidNumberFormat. The encoder writes numbers in the same format.
+	DecimalSeparator rune
+	GroupSeparator   rune
+	// NumberSuffixes defines units like "€" or "%" that may follow number cells. They are removed while reading,
+	// without changing the value, and are not written.
+	NumberSuffixes []string
+	// Encoding defines the character encoding of the data that is read. Byte order marks are removed and
+	// data with the byte order mark of UTF-16 is always read as UTF-16. If it is not set, UTF-8 is used.
+	Encoding Encoding
+	// SniffDialect defines whether the delimiter and comment character are detected with Sniff when reading,
+	// replacing Comma and Comment. The first 64 KiB of the data are examined.
+	SniffDialect bool
+	// SampleRows defines how many data rows InferTypes examines.
+	// If it is not set, all rows are examined.
+	SampleRows int
+	// Location defines the time zone of time values without offset.
+	// If it is not set, UTC is used.
+	Location *time.Location
 	// isTyped defines whether the user expected to receive a typed or untyped response.
 	isTyped bool
+	// types contains the custom types registered with RegisterType on this parser.
+	types map[string]TypeFunc
 }
 
 // Untyped unmarshals the data into a slice of map[string]interface{}
 func (c *CSVParser) Untyped(data []byte) ([]map[string]interface{}, error) {
-	c.isTyped = false
-	return c.parseToCSV(data)
+	parser := *c
+	parser.isTyped = false
+	return parser.parseToCSV(data)
 }
 
 // Typed unmarshals the typed data into a slice of map[string]interface{}
 //
 // In this case, the second column of the csv must contain the field types, otherwise it will throw an error
 func (c *CSVParser) Typed(data []byte) ([]map[string]interface{}, error) {
-	c.isTyped = true
-	return c.parseToCSV(data)
+	parser := *c
+	parser.isTyped = true
+	return parser.parseToCSV(data)
 }
 
 // checkForNilOrDefault checks if the runes are set.
@@ -70,16 +142,21 @@ func (c *CSVParser) checkForNilOrDefault() {
 	}
 }
 
-// readCSV delegates the read command to csv.NewReader (stdlib) and writes it to a two-dimensional string slice that is returned.
-func (c *CSVParser) readCSV(data []byte) ([][]string, error) {
-	csvR := csv.NewReader(bytes.NewReader(data))
+// newReader creates a csv.Reader (stdlib) for r that is configured with the settings of the parser.
+func (c *CSVParser) newReader(r io.Reader) *csv.Reader {
+	csvR := csv.NewReader(r)
 	csvR.Comma = c.Comma
 	csvR.Comment = c.Comment
 	csvR.TrimLeadingSpace = c.TrimLeadingSpace
 	csvR.FieldsPerRecord = -1
 	csvR.LazyQuotes = true
 
-	records, err := csvR.ReadAll()
+	return csvR
+}
+
+// readCSV delegates the read command to csv.NewReader (stdlib) and writes it to a two-dimensional string slice that is returned.
+func (c *CSVParser) readCSV(data []byte) ([][]string, error) {
+	records, err := c.newReader(c.decodeReader(bytes.NewReader(data))).ReadAll()
 	if err != nil {
 		return nil, err
 	}
@@ -89,31 +166,7 @@ func (c *CSVParser) readCSV(data []byte) ([][]string, error) {
 
 // parseToCSV extracts the header information from the byte slice and generates a map based on the format (typed or untyped).
 func (c *CSVParser) parseToCSV(data []byte) ([]map[string]interface{}, error) {
-	c.checkForNilOrDefault()
-
-	records, err := c.readCSV(data)
-	if err != nil {
-		return nil, err
-	}
-
-	var headerInfo map[int]field
-	if c.isTyped {
-		if len(records) < 2 {
-			return nil, ErrDataIsNil
-		}
-
-		headerInfo = c.extractHeaderInformation(records[0], records[1])
-		records = records[2:]
-	} else {
-		if len(records) < 1 {
-			return nil, ErrDataIsNil
-		}
-
-		headerInfo = c.extractHeaderInformation(records[0], nil)
-		records = records[1:]
-	}
-
-	return c.csvToMap(headerInfo, records)
+	return c.decodeAll(c.newDecoder(bytes.NewReader(data), c.isTyped))
 }
 
 // extractHeaderInformation reads the header information and returns it as map of field
@@ -139,66 +192,151 @@ func (c *CSVParser) extractHeaderInformation(names, types []string) map[int]fiel
 
 // csvToMap builds the data columns based on the typed or untyped fields
 func (c *CSVParser) csvToMap(headerInfo map[int]field, records [][]string) ([]map[string]interface{}, error) {
+	if headerInfo == nil {
+		headerInfo = map[int]field{}
+	}
+
+	return c.decodeAll(&Decoder{
+		parser:     *c,
+		reader:     &recordSlice{records: records},
+		headerInfo: headerInfo,
+	})
+}
+
+// decodeAll collects all rows of the decoder.
+func (c *CSVParser) decodeAll(dec *Decoder) ([]map[string]interface{}, error) {
 	rslt := []map[string]interface{}{}
+	for dec.Next() {
+		rslt = append(rslt, dec.Row())
+	}
+	if err := dec.Err(); err != nil {
+		return nil, err
+	}
 
-	// skip first row
-	for _, value := range records {
-		skipColumn := true
+	return rslt, nil
+}
 
-		myColumn := make(map[string]interface{})
-		for idx, v2 := range value {
-			if len(headerInfo) < idx {
-				// the column contains more data than we expected, break out of it
-				break
-			}
+// rowToRow builds a single data column based on the typed or untyped fields, in the order of the header.
+// The returned bool reports whether the row is empty and should be skipped.
+// line is the source line of the row, which is reported in a *ParseError.
+//
+// All cells of the row are converted, even if one of them fails. The errors of all failed cells are returned.
+func (c *CSVParser) rowToRow(headerInfo map[int]field, value []string, line int) (Row, bool, ParseErrors) {
+	if err := c.checkRecordWidth(headerInfo, value, line); err != nil {
+		return Row{}, false, ParseErrors{err}
+	}
 
-			// checks if the first entry of the row and the first character of the string matches the comment character.
-			// If it matches, this row is skipped.
-			// This is necessary because csvR.ReadAll() ignores some cases that contain such a comment rune
-			if idx == 0 && len(v2) > 0 {
-				if rune(v2[0]) == c.Comment {
-					// the column contains the comment rune, skip it
-					break
-				}
+	var errs, violations ParseErrors
+	var overflow []string
+	skipColumn := true
+
+	myColumn := Row{}
+	for idx, v2 := range value {
+		if idx >= len(headerInfo) {
+			// the column contains more data than we expected, collect it if an overflow key is set
+			if c.OverflowKey == "" {
+				break
 			}
 
-			// check whether v2 contains a value or not
-			// set skip column to false, if a value was set
 			if len(v2) > 0 {
 				skipColumn = false
 			}
+			overflow = append(overflow, v2)
+			continue
+		}
 
-			// check whether isTyped is true, the header info is not set and skip columns is set
-			// then this row should be skipped
-			if c.isTyped && headerInfo[idx].Type == "" && c.SkipEmptyColumns {
-				continue
-			}
+		// check whether v2 contains a value or not
+		// set skip column to false, if a value was set
+		if len(v2) > 0 {
+			skipColumn = false
+		}
 
-			// check whether the type was set for the row
-			if headerInfo[idx].Type != "" {
-				// toTyped returns the
-				typed, err := c.toTyped(v2, strings.TrimPrefix(headerInfo[idx].Type, "*"), strings.HasPrefix(headerInfo[idx].Type, "*"))
-				if err != nil {
-					return nil, err
-				}
+		// check whether isTyped is true, the header info is not set and skip columns is set
+		// then this row should be skipped
+		if c.isTyped && headerInfo[idx].Type == "" && c.SkipEmptyColumns {
+			continue
+		}
 
-				// type is not a pointer
-				myColumn[headerInfo[idx].Name] = typed
+		var cell interface{} = v2
+
+		// check whether the type was set for the row
+		if headerInfo[idx].Type != "" {
+			if v2 == "" && headerInfo[idx].HasDefault {
+				v2 = headerInfo[idx].Default
+			}
+
+			typed, err := c.convertCell(v2, headerInfo[idx].Type)
+			if err != nil {
+				errs = append(errs, &ParseError{
+					Line:   line,
+					Column: idx + 1,
+					Name:   headerInfo[idx].Name,
+					Type:   headerInfo[idx].Type,
+					Value:  v2,
+					Err:    err,
+				})
 				continue
 			}
 
-			myColumn[headerInfo[idx].Name] = v2
+			cell = typed
+		} else if c.isNull(v2) {
+			cell = nil
 		}
-		if !skipColumn {
-			rslt = append(rslt, myColumn)
+
+		if err := headerInfo[idx].constraint.check(v2, cell, v2 == "" || c.isNull(v2), line); err != nil {
+			violations = append(violations, &ParseError{
+				Line:   line,
+				Column: idx + 1,
+				Name:   headerInfo[idx].Name,
+				Type:   headerInfo[idx].Type,
+				Value:  v2,
+				Err:    err,
+			})
 		}
+
+		myColumn.addField(headerInfo[idx], cell)
 	}
 
-	return rslt, nil
+	// cells that are missing, because the row is shorter than the header, can violate the required constraint
+	for idx := len(value); idx < len(headerInfo); idx++ {
+		if err := headerInfo[idx].constraint.check("", nil, true, line); err != nil {
+			violations = append(violations, &ParseError{
+				Line:   line,
+				Column: idx + 1,
+				Name:   headerInfo[idx].Name,
+				Type:   headerInfo[idx].Type,
+				Err:    err,
+			})
+		}
+	}
+
+	if overflow != nil {
+		myColumn.add(c.OverflowKey, overflow)
+	}
+
+	// rows that are skipped do not violate any constraint
+	if !skipColumn {
+		errs = append(errs, violations...)
+	}
+
+	if len(errs) > 0 {
+		return Row{}, false, errs
+	}
+
+	return myColumn, skipColumn, nil
 }
 
 // toTyped takes the value and the format and converts the value into the desired format.
 func (c *CSVParser) toTyped(value, format string, isPointer bool) (interface{}, error) {
+	if _, _, ok := splitArraySeparator(format); ok {
+		parser, base, err := c.withArraySeparator(format)
+		if err != nil {
+			return nil, err
+		}
+
+		return parser.toTyped(value, base, isPointer)
+	}
+
 	switch format {
 	case "string":
 		if value == "" && !isPointer {
@@ -219,7 +357,12 @@ func (c *CSVParser) toTyped(value, format string, isPointer bool) (interface{},
 			return nil, nil
 		}
 
-		val, err := strconv.ParseInt(value, 10, 64)
+		num, err := c.normalizeNumber(value)
+		if err != nil {
+			return nil, err
+		}
+
+		val, err := strconv.ParseInt(num, 10, 64)
 		if isPointer {
 			return &val, err
 		}
@@ -232,7 +375,12 @@ func (c *CSVParser) toTyped(value, format string, isPointer bool) (interface{},
 			return nil, nil
 		}
 
-		val, err := strconv.Atoi(value)
+		num, err := c.normalizeNumber(value)
+		if err != nil {
+			return nil, err
+		}
+
+		val, err := strconv.Atoi(num)
 		if isPointer {
 			return &val, err
 		}
@@ -245,7 +393,12 @@ func (c *CSVParser) toTyped(value, format string, isPointer bool) (interface{},
 			return nil, nil
 		}
 
-		val, err := strconv.ParseFloat(value, 64)
+		num, err := c.normalizeNumber(value)
+		if err != nil {
+			return nil, err
+		}
+
+		val, err := strconv.ParseFloat(num, 64)
 		if isPointer {
 			return &val, err
 		}
@@ -257,7 +410,7 @@ func (c *CSVParser) toTyped(value, format string, isPointer bool) (interface{},
 			return nil, nil
 		}
 
-		val, err := strconv.ParseBool(value)
+		val, err := c.parseBool(value)
 		if isPointer {
 			return &val, err
 		}
@@ -269,7 +422,7 @@ func (c *CSVParser) toTyped(value, format string, isPointer bool) (interface{},
 			return nil, nil
 		}
 
-		records, err := c.readCSV([]byte(value))
+		records, err := c.readArray(value)
 		if err != nil {
 
 			return nil, err
@@ -295,7 +448,7 @@ func (c *CSVParser) toTyped(value, format string, isPointer bool) (interface{},
 			return nil, nil
 		}
 
-		records, err := c.readCSV([]byte(value))
+		records, err := c.readArray(value)
 		if err != nil {
 			return nil, err
 		}
@@ -309,7 +462,12 @@ func (c *CSVParser) toTyped(value, format string, isPointer bool) (interface{},
 		for _, v := range records[0] {
 			vi := int64(0)
 			if v != "" {
-				vi, err = strconv.ParseInt(strings.TrimSpace(v), 10, 64)
+				num, err := c.normalizeNumber(v)
+				if err != nil {
+					return nil, err
+				}
+
+				vi, err = strconv.ParseInt(num, 10, 64)
 				if err != nil {
 					return nil, err
 				}
@@ -329,7 +487,7 @@ func (c *CSVParser) toTyped(value, format string, isPointer bool) (interface{},
 			return nil, nil
 		}
 
-		records, err := c.readCSV([]byte(value))
+		records, err := c.readArray(value)
 		if err != nil {
 			return nil, err
 		}
@@ -344,7 +502,12 @@ func (c *CSVParser) toTyped(value, format string, isPointer bool) (interface{},
 		for _, v := range records[0] {
 			vi := float64(0)
 			if v != "" {
-				vi, err = strconv.ParseFloat(strings.TrimSpace(v), 64)
+				num, err := c.normalizeNumber(v)
+				if err != nil {
+					return nil, err
+				}
+
+				vi, err = strconv.ParseFloat(num, 64)
 				if err != nil {
 					return nil, err
 				}
@@ -364,7 +527,7 @@ func (c *CSVParser) toTyped(value, format string, isPointer bool) (interface{},
 			return nil, nil
 		}
 
-		records, err := c.readCSV([]byte(value))
+		records, err := c.readArray(value)
 		if err != nil {
 			return nil, err
 		}
@@ -376,7 +539,14 @@ func (c *CSVParser) toTyped(value, format string, isPointer bool) (interface{},
 
 		retArray := make([]bool, 0)
 		for _, v := range records[0] {
-			retArray = append(retArray, strings.TrimSpace(v) == "true")
+			vi := false
+			if v != "" {
+				vi, err = c.parseBool(v)
+				if err != nil {
+					return nil, err
+				}
+			}
+			retArray = append(retArray, vi)
 		}
 
 		if isPointer {
@@ -403,6 +573,30 @@ func (c *CSVParser) toTyped(value, format string, isPointer bool) (interface{},
 
 		return data, nil
 	default:
-		return nil, fmt.Errorf("%w: %s", ErrUnsupportedType, format)
+		name, layout := splitFormat(strings.TrimSuffix(format, ",array"))
+		if fn, elemType, ok := c.timeType(name, layout); ok {
+			return c.convertWith(value, fn, elemType, strings.HasSuffix(format, ",array"), isPointer)
+		}
+		if fn, elemType, ok := numberType(name); ok {
+			return c.convertWith(value, c.withNumberFormat(fn), elemType, strings.HasSuffix(format, ",array"), isPointer)
+		}
+		if fn, elemType, ok := enumType(name, layout); ok {
+			return c.convertWith(value, fn, elemType, strings.HasSuffix(format, ",array"), isPointer)
+		}
+		if fn, elemType, ok := bigType(name); ok {
+			fn = c.withNumberFormat(fn)
+			if isPointer && !strings.HasSuffix(format, ",array") {
+				// big numbers are pointers already, so the pointer type only differs for empty values
+				if value == "" {
+					return nil, nil
+				}
+
+				return fn(value)
+			}
+
+			return c.convertWith(value, fn, elemType, strings.HasSuffix(format, ",array"), isPointer)
+		}
+
+		return c.customToTyped(value, format, isPointer)
 	}
 }
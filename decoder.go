@@ -0,0 +1,377 @@
+package csvx
+
+import (
+	"bufio"
+	"bytes"
+	"io"
+	"strings"
+)
+
+// Decoder reads a csv from an input stream and converts it row by row.
+//
+// In contrast to Typed and Untyped, the decoder never holds more than one row in memory.
+type Decoder struct {
+	// parser holds a copy of the settings the decoder was created with.
+	parser CSVParser
+	// reader is the underlying csv reader.
+	reader recordReader
+	// lines counts the lines the csv reader consumed from the input stream.
+	// It is nil if the records do not come from an input stream.
+	lines *lineCounter
+	// records is the number of records read so far.
+	records int
+	// line is the source line on which the current record starts.
+	line int
+	// quoted reports whether the first cell of the current record is quoted.
+	quoted bool
+	// record contains the raw cells of the current row.
+	record []string
+	// collectErrors defines whether rows with invalid cells are skipped instead of stopping the decoder.
+	collectErrors bool
+	// errs contains the errors of the skipped rows, if collectErrors is set.
+	errs ParseErrors
+	// headerInfo contains the field names and types, once the header was read.
+	headerInfo map[int]field
+	// types replaces the type row of typed csv, if it is set.
+	types []string
+	// paths contains the nested paths of the header names, if NestedHeaders is set.
+	paths map[string][]pathElem
+	// row contains the current row.
+	row Row
+	// hasRow reports whether there is a current row.
+	hasRow bool
+	// rowMap caches the current row as map, once it was requested.
+	rowMap map[string]interface{}
+	// err contains the first error that occurred while decoding.
+	err error
+}
+
+// recordReader reads one csv record at a time, like csv.Reader (stdlib) does.
+type recordReader interface {
+	Read() ([]string, error)
+}
+
+// NewDecoder returns a new decoder that reads untyped rows from r using the default settings.
+func NewDecoder(r io.Reader) *Decoder {
+	return (&CSVParser{}).UntypedDecoder(r)
+}
+
+// UntypedDecoder returns a new decoder that reads untyped rows from r using the settings of the parser.
+func (c *CSVParser) UntypedDecoder(r io.Reader) *Decoder {
+	return c.newDecoder(r, false)
+}
+
+// TypedDecoder returns a new decoder that reads typed rows from r using the settings of the parser.
+//
+// The second row of the csv must contain the field types, otherwise an error is returned.
+func (c *CSVParser) TypedDecoder(r io.Reader) *Decoder {
+	return c.newDecoder(r, true)
+}
+
+// newDecoder copies the parser settings, so that later changes to the parser do not affect the decoder.
+func (c *CSVParser) newDecoder(r io.Reader, isTyped bool) *Decoder {
+	parser := *c
+	parser.isTyped = isTyped
+	parser.checkForNilOrDefault()
+
+	src := bufio.NewReader(parser.decodeReader(r))
+	if parser.SniffDialect {
+		// the buffer holds the sample, so that it is read again by the csv reader
+		src = bufio.NewReaderSize(src, sniffSampleSize)
+
+		// Peek returns a shorter sample with an error, if the data is shorter
+		sample, err := src.Peek(sniffSampleSize)
+		if dialect, err := parser.sniff(sample, err == nil); err == nil {
+			parser.applyDialect(dialect)
+		}
+	}
+
+	lines := &lineCounter{r: src}
+
+	return &Decoder{
+		parser: parser,
+		reader: parser.newReader(lines),
+		lines:  lines,
+	}
+}
+
+// Next advances the decoder to the next row, which is then available through Row.
+// It returns false when the end of the input is reached or an error occurred.
+// In the latter case, Err returns the error.
+func (d *Decoder) Next() bool {
+	d.row, d.hasRow, d.rowMap = Row{}, false, nil
+	if !d.ensureHeader() {
+		return false
+	}
+
+	for {
+		record, err := d.read()
+		if err == io.EOF {
+			return false
+		}
+		if err != nil {
+			d.err = err
+			return false
+		}
+
+		if d.isComment(record) {
+			continue
+		}
+
+		row, skip, errs := d.parser.rowToRow(d.headerInfo, record, d.line)
+		if len(errs) > 0 {
+			if d.collectErrors {
+				// continue with the next row, the caller gets all errors at the end
+				d.errs = append(d.errs, errs...)
+				continue
+			}
+
+			d.err = errs[0]
+			return false
+		}
+		if skip {
+			continue
+		}
+
+		d.record = record
+		d.row, d.hasRow = row, true
+		return true
+	}
+}
+
+// Row returns the row read by the last call to Next.
+// If NestedHeaders is set, the values are nested into maps and slices by their header paths.
+func (d *Decoder) Row() map[string]interface{} {
+	if !d.hasRow {
+		return nil
+	}
+
+	if d.rowMap == nil {
+		if d.paths != nil {
+			d.rowMap = nestRow(d.row, d.paths)
+		} else {
+			d.rowMap = d.row.Map()
+		}
+	}
+
+	return d.rowMap
+}
+
+// OrderedRow returns the row read by the last call to Next, with the columns in the order of the header.
+// The columns keep their header names, even if NestedHeaders is set.
+func (d *Decoder) OrderedRow() Row {
+	return d.row
+}
+
+// Line returns the source line on which the row read by the last call to Next starts.
+//
+// If the decoder does not read from an input stream, the number of the record is returned instead.
+func (d *Decoder) Line() int {
+	return d.line
+}
+
+// Err returns the first error that occurred while decoding, if any.
+func (d *Decoder) Err() error {
+	return d.err
+}
+
+// ensureHeader reads the header, if this has not happened yet.
+// It returns false if the header could not be read.
+func (d *Decoder) ensureHeader() bool {
+	if d.err != nil {
+		return false
+	}
+
+	if d.headerInfo == nil {
+		d.err = d.readHeader()
+	}
+
+	return d.err == nil
+}
+
+// readHeader reads the field names and, for typed csv, the field types.
+func (d *Decoder) readHeader() error {
+	names, err := d.readHeaderRecord()
+	if err != nil {
+		return err
+	}
+
+	types := d.types
+	if d.parser.isTyped && types == nil {
+		types, err = d.readHeaderRecord()
+		if err != nil {
+			return err
+		}
+	}
+
+	var specs []string
+	if d.parser.ConstraintRow {
+		specs, err = d.readHeaderRecord()
+		if err != nil {
+			return err
+		}
+	}
+
+	headerInfo := d.parser.extractHeaderInformation(names, types)
+
+	headerInfo, err = d.parser.checkHeader(headerInfo)
+	if err != nil {
+		return err
+	}
+
+	if d.parser.isTyped {
+		if err := checkEnums(headerInfo); err != nil {
+			return err
+		}
+
+		headerInfo, err = d.parser.checkDefaults(headerInfo)
+		if err != nil {
+			return err
+		}
+	}
+
+	if d.parser.ConstraintRow || d.parser.Constraints != nil {
+		headerInfo, err = d.parser.checkConstraints(headerInfo, specs)
+		if err != nil {
+			return err
+		}
+	}
+
+	if d.parser.NestedHeaders {
+		d.paths, err = d.parser.headerPaths(headerInfo)
+		if err != nil {
+			return err
+		}
+	}
+
+	d.headerInfo = headerInfo
+	return nil
+}
+
+// readHeaderRecord reads a single header record and returns ErrDataIsNil if there is none.
+func (d *Decoder) readHeaderRecord() ([]string, error) {
+	record, err := d.read()
+	if err == io.EOF {
+		return nil, ErrDataIsNil
+	}
+
+	return record, err
+}
+
+// read reads the next record and determines the source line on which it starts.
+func (d *Decoder) read() ([]string, error) {
+	record, err := d.reader.Read()
+	if err != nil {
+		return nil, err
+	}
+
+	d.records++
+	if d.lines == nil {
+		d.line = d.records
+		d.quoted = false
+		return record, nil
+	}
+
+	// the csv reader stops at the end of the record, so the counted lines end with the last line of the record.
+	// Quoted values that span several lines contain the line breaks.
+	d.line = d.lines.current()
+	for _, value := range record {
+		d.line -= strings.Count(value, "\n")
+	}
+	d.quoted = d.lines.startsQuoted(d.line)
+
+	return record, nil
+}
+
+// isComment reports whether the record is a comment that csv.Reader (stdlib) did not skip, because the
+// comment rune follows leading spaces. Quoted cells are never comments.
+func (d *Decoder) isComment(record []string) bool {
+	return len(record) > 0 && strings.HasPrefix(record[0], string(d.parser.Comment)) && !d.quoted
+}
+
+// recordSlice implements recordReader for records that were read already.
+type recordSlice struct {
+	records [][]string
+}
+
+// Read returns the next record of the slice.
+func (r *recordSlice) Read() ([]string, error) {
+	if len(r.records) == 0 {
+		return nil, io.EOF
+	}
+
+	record := r.records[0]
+	r.records = r.records[1:]
+	return record, nil
+}
+
+// lineCounter counts the lines read from the input stream.
+//
+// Every call to Read returns at most one line, so the buffer of the csv reader never contains more
+// than the record it is currently reading. This way, the counted lines always end with the last record.
+type lineCounter struct {
+	r *bufio.Reader
+	// lines is the number of complete lines read so far.
+	lines int
+	// partial reports whether the last line was read without its line break.
+	partial bool
+	// quoted contains the lines, which have not been passed yet, whose first cell is quoted.
+	quoted map[int]bool
+}
+
+// Read reads up to the next line break.
+func (l *lineCounter) Read(p []byte) (int, error) {
+	if len(p) == 0 {
+		return 0, nil
+	}
+
+	_, err := l.r.Peek(1)
+	if err != nil {
+		return 0, err
+	}
+
+	buf, _ := l.r.Peek(l.r.Buffered())
+	if idx := bytes.IndexByte(buf, '\n'); idx >= 0 {
+		buf = buf[:idx+1]
+	}
+
+	n := copy(p, buf)
+	_, _ = l.r.Discard(n)
+
+	if !l.partial && bytes.HasPrefix(bytes.TrimLeft(p[:n], " \t"), []byte{'"'}) {
+		if l.quoted == nil {
+			l.quoted = map[int]bool{}
+		}
+		l.quoted[l.lines+1] = true
+	}
+
+	if p[n-1] == '\n' {
+		l.lines++
+		l.partial = false
+	} else {
+		l.partial = true
+	}
+
+	return n, nil
+}
+
+// current returns the number of the line that was read last.
+func (l *lineCounter) current() int {
+	if l.partial {
+		return l.lines + 1
+	}
+
+	return l.lines
+}
+
+// startsQuoted reports whether the first cell of the line is quoted and forgets the lines up to the line.
+func (l *lineCounter) startsQuoted(line int) bool {
+	quoted := l.quoted[line]
+	for key := range l.quoted {
+		if key <= line {
+			delete(l.quoted, key)
+		}
+	}
+
+	return quoted
+}
@@ -0,0 +1,155 @@
+package csvx
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestDecoder_Next(t *testing.T) {
+	type args struct {
+		data    string
+		isTyped bool
+	}
+	tests := []struct {
+		name    string
+		args    args
+		want    []map[string]interface{}
+		wantErr error
+	}{
+		{
+			name: "test_untyped",
+			args: args{
+				data: `
+				foo,bar
+				first,second
+				third,fourth`,
+			},
+			want: []map[string]interface{}{
+				{
+					"foo": "first",
+					"bar": "second",
+				},
+				{
+					"foo": "third",
+					"bar": "fourth",
+				},
+			},
+		},
+		{
+			name: "test_typed",
+			args: args{
+				data: `
+				foo,bar
+				string,int64
+				first,10
+				,
+				# third,20
+				third,20`,
+				isTyped: true,
+			},
+			want: []map[string]interface{}{
+				{
+					"foo": "first",
+					"bar": int64(10),
+				},
+				{
+					"foo": "third",
+					"bar": int64(20),
+				},
+			},
+		},
+		{
+			name: "test_typed_without_rows",
+			args: args{
+				data: `
+				foo,bar
+				string,int64`,
+				isTyped: true,
+			},
+			want: []map[string]interface{}{},
+		},
+		{
+			name: "test_typed_without_type_row",
+			args: args{
+				data:    `foo,bar`,
+				isTyped: true,
+			},
+			want:    []map[string]interface{}{},
+			wantErr: ErrDataIsNil,
+		},
+		{
+			name: "test_typed_unsupported_type",
+			args: args{
+				data: `
+				foo,bar
+				string,unknown
+				first,second`,
+				isTyped: true,
+			},
+			want:    []map[string]interface{}{},
+			wantErr: ErrUnsupportedType,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			csv := CSVParser{Comma: ',', Comment: '#', TrimLeadingSpace: true}
+
+			var dec *Decoder
+			if tt.args.isTyped {
+				dec = csv.TypedDecoder(strings.NewReader(tt.args.data))
+			} else {
+				dec = csv.UntypedDecoder(strings.NewReader(tt.args.data))
+			}
+
+			rslt := []map[string]interface{}{}
+			for dec.Next() {
+				rslt = append(rslt, dec.Row())
+			}
+
+			if !errors.Is(dec.Err(), tt.wantErr) {
+				t.Errorf("TestDecoderNext() received error = %v, want %v", dec.Err(), tt.wantErr)
+			}
+
+			if !reflect.DeepEqual(rslt, tt.want) {
+				t.Errorf("TestDecoderNext() is not equal. \ngot = %+#v\nwant = %+#v", rslt, tt.want)
+			}
+
+			if dec.Next() || dec.Row() != nil {
+				t.Errorf("TestDecoderNext() returned a row after the end of the input")
+			}
+		})
+	}
+}
+
+func TestNewDecoder(t *testing.T) {
+	dec := NewDecoder(strings.NewReader("foo;bar\nfirst;second"))
+
+	if !dec.Next() {
+		t.Fatalf("TestNewDecoder() received no row, error = %v", dec.Err())
+	}
+
+	want := map[string]interface{}{"foo;bar": "first;second"}
+	if !reflect.DeepEqual(dec.Row(), want) {
+		t.Errorf("TestNewDecoder() is not equal. \ngot = %+#v\nwant = %+#v", dec.Row(), want)
+	}
+}
+
+func TestDecoder_Line(t *testing.T) {
+	csv := CSVParser{Comma: ',', Comment: '#'}
+	dec := csv.UntypedDecoder(strings.NewReader("foo,bar\n\n# comment\nfirst,\"multi\nline\"\r\nsecond,third"))
+
+	lines := []int{}
+	for dec.Next() {
+		lines = append(lines, dec.Line())
+	}
+	if dec.Err() != nil {
+		t.Fatalf("TestDecoderLine() received error = %v", dec.Err())
+	}
+
+	want := []int{4, 6}
+	if !reflect.DeepEqual(lines, want) {
+		t.Errorf("TestDecoderLine() is not equal. \ngot = %+#v\nwant = %+#v", lines, want)
+	}
+}
@@ -0,0 +1,548 @@
+package csvx
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+var ErrInvalidMarshalSource = errors.New("marshal source must be a map[string]interface{}, a Row, a struct or a slice of them")
+
+// Encoder writes typed csv to an output stream.
+//
+// The output consists of the name row, the type row and the data rows, so it can be read again with Typed.
+type Encoder struct {
+	// parser holds a copy of the settings the encoder was created with.
+	parser CSVParser
+	// w is the output stream of the csv writer.
+	w io.Writer
+	// writer is the underlying csv writer.
+	writer *csv.Writer
+	// header contains the field names and types in column order, once the header was written.
+	header []field
+}
+
+// NewEncoder returns a new encoder that writes to w using the default settings.
+func NewEncoder(w io.Writer) *Encoder {
+	return (&CSVParser{}).NewEncoder(w)
+}
+
+// NewEncoder returns a new encoder that writes to w using the settings of the parser.
+func (c *CSVParser) NewEncoder(w io.Writer) *Encoder {
+	parser := *c
+	parser.isTyped = true
+	parser.checkForNilOrDefault()
+
+	writer := csv.NewWriter(w)
+	writer.Comma = parser.Comma
+
+	return &Encoder{
+		parser: parser,
+		w:      w,
+		writer: writer,
+	}
+}
+
+// Marshal returns the typed csv encoding of v using the default settings.
+//
+// See Encoder.Encode for details.
+func Marshal(v interface{}) ([]byte, error) {
+	return (&CSVParser{}).Marshal(v)
+}
+
+// Marshal returns the typed csv encoding of v using the settings of the parser.
+//
+// See Encoder.Encode for details.
+func (c *CSVParser) Marshal(v interface{}) ([]byte, error) {
+	var buf bytes.Buffer
+
+	err := c.NewEncoder(&buf).Encode(v)
+	if err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+// Encode writes v as csv rows. v must be a map[string]interface{}, a Row, a struct, a pointer to a struct
+// or a slice of them.
+//
+// The first call to Encode writes the header. For structs, the columns are named by the `csv` tags
+// in field order and typed by the go types of the fields, unless the tag declares the type after the name,
+// like `csv:"status,enum(draft|published)"`. For maps, the columns are sorted by name
+// and typed by the first value of each column that is not nil. Rows keep their column order. Go types that have no matching
+// type name are written as json. Later calls to Encode reuse the header. An empty slice of structs writes
+// only the header, other values without columns write nothing.
+//
+// If NestedHeaders is set, nested maps and slices of maps are flattened into columns named by the header paths
+// of their leaves, like "address.street" and "tags[0]", so that Typed builds the same structure again.
+//
+// nil values are written as empty cells, which Typed reads back as nil only for pointer types and json.
+// If NullTokens is set, nil values are written as the first null token instead.
+func (e *Encoder) Encode(v interface{}) error {
+	rows, err := encoderRows(reflect.ValueOf(v))
+	if err != nil {
+		return err
+	}
+
+	if e.parser.NestedHeaders {
+		rows, err = flattenRows(rows)
+		if err != nil {
+			return err
+		}
+	}
+
+	if e.header == nil {
+		header := encoderHeader(rows)
+		if len(rows) == 0 {
+			// an empty slice of structs still defines the columns
+			if structType, ok := structElemType(reflect.ValueOf(v)); ok {
+				header = encoderHeader([]encoderRow{{columns: collectStructFields(structType, nil)}})
+			}
+		}
+		if len(header) == 0 {
+			// without columns, neither the header nor rows can be written
+			return nil
+		}
+		e.header = header
+
+		if e.parser.NestedHeaders {
+			// leaves of different rows may conflict, like "address" in one row and "address.street" in another
+			headerInfo := make(map[int]field, len(e.header))
+			for idx, hf := range e.header {
+				headerInfo[idx] = hf
+			}
+
+			if _, err := e.parser.headerPaths(headerInfo); err != nil {
+				e.header = nil
+				return err
+			}
+		}
+
+		names := make([]string, len(e.header))
+		types := make([]string, len(e.header))
+		for idx, hf := range e.header {
+			names[idx] = hf.Name
+			types[idx] = hf.Type
+		}
+
+		if err := e.writeRecord(names); err != nil {
+			return err
+		}
+		if err := e.writeRecord(types); err != nil {
+			return err
+		}
+	}
+
+	for _, row := range rows {
+		record := make([]string, len(e.header))
+		for idx, hf := range e.header {
+			value, ok := row.get(hf.Name)
+			if !ok {
+				continue
+			}
+
+			record[idx], err = e.parser.fromTyped(value, strings.TrimPrefix(hf.Type, "*"))
+			if err != nil {
+				return fmt.Errorf("column %q: %w", hf.Name, err)
+			}
+		}
+
+		if err := e.writeRecord(record); err != nil {
+			return err
+		}
+	}
+
+	e.writer.Flush()
+	return e.writer.Error()
+}
+
+// writeRecord writes the record with the csv writer.
+//
+// csv.Writer (stdlib) does not quote a first cell that starts with the comment rune, so the reader would skip
+// the line as comment. Such a cell is quoted here and the other cells are written by the csv writer.
+func (e *Encoder) writeRecord(record []string) error {
+	if len(record) == 0 || !strings.HasPrefix(record[0], string(e.parser.Comment)) {
+		return e.writer.Write(record)
+	}
+
+	e.writer.Flush()
+	if err := e.writer.Error(); err != nil {
+		return err
+	}
+
+	_, err := io.WriteString(e.w, `"`+strings.ReplaceAll(record[0], `"`, `""`)+`"`)
+	if err != nil {
+		return err
+	}
+
+	if len(record) == 1 {
+		_, err = io.WriteString(e.w, "\n")
+		return err
+	}
+
+	_, err = io.WriteString(e.w, string(e.parser.Comma))
+	if err != nil {
+		return err
+	}
+
+	return e.writer.Write(record[1:])
+}
+
+// encoderRow gives access to the columns of a map, struct or Row that is encoded.
+type encoderRow struct {
+	value   reflect.Value
+	columns []structColumn
+	row     *Row
+	// nested defines whether row contains the flattened leaves of a map.
+	nested bool
+}
+
+// get returns the value of the named column.
+func (r encoderRow) get(name string) (interface{}, bool) {
+	if r.row != nil {
+		return r.row.Get(name)
+	}
+
+	if r.columns == nil {
+		value := r.value.MapIndex(reflect.ValueOf(name))
+		if !value.IsValid() {
+			return nil, false
+		}
+
+		return value.Interface(), true
+	}
+
+	for _, column := range r.columns {
+		if column.name == name {
+			return r.value.FieldByIndex(column.index).Interface(), true
+		}
+	}
+
+	return nil, false
+}
+
+// encoderRows converts v into the list of rows that are encoded.
+func encoderRows(v reflect.Value) ([]encoderRow, error) {
+	for v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return nil, ErrInvalidMarshalSource
+		}
+		v = v.Elem()
+	}
+
+	if !v.IsValid() {
+		return nil, ErrInvalidMarshalSource
+	}
+
+	if row, ok := v.Interface().(Row); ok {
+		return []encoderRow{{row: &row}}, nil
+	}
+
+	switch v.Kind() {
+	case reflect.Map:
+		if v.Type().Key().Kind() != reflect.String {
+			return nil, ErrInvalidMarshalSource
+		}
+
+		return []encoderRow{{value: v}}, nil
+	case reflect.Struct:
+		return []encoderRow{{value: v, columns: collectStructFields(v.Type(), nil)}}, nil
+	case reflect.Slice, reflect.Array:
+		rows := []encoderRow{}
+		for i := 0; i < v.Len(); i++ {
+			row, err := encoderRows(v.Index(i))
+			if err != nil {
+				return nil, err
+			}
+
+			rows = append(rows, row...)
+		}
+
+		return rows, nil
+	default:
+		return nil, ErrInvalidMarshalSource
+	}
+}
+
+// structElemType returns the struct type of the elements, if v is a slice or array of structs or struct pointers.
+func structElemType(v reflect.Value) (reflect.Type, bool) {
+	for v.Kind() == reflect.Ptr && !v.IsNil() {
+		v = v.Elem()
+	}
+	if v.Kind() != reflect.Slice && v.Kind() != reflect.Array {
+		return nil, false
+	}
+
+	elemType := v.Type().Elem()
+	if elemType.Kind() == reflect.Ptr {
+		elemType = elemType.Elem()
+	}
+	if elemType.Kind() != reflect.Struct || elemType == reflect.TypeOf(Row{}) {
+		return nil, false
+	}
+
+	return elemType, true
+}
+
+// flattenRows replaces the maps of the rows with rows of their flattened leaves, see NestedHeaders.
+func flattenRows(rows []encoderRow) ([]encoderRow, error) {
+	for idx, row := range rows {
+		if row.columns != nil || row.row != nil {
+			continue
+		}
+
+		flat, err := flattenMap(row.value)
+		if err != nil {
+			return nil, err
+		}
+
+		rows[idx] = encoderRow{row: &flat, nested: true}
+	}
+
+	return rows, nil
+}
+
+// encoderHeader determines the field names and types of the rows.
+func encoderHeader(rows []encoderRow) []field {
+	header := []field{}
+	known := map[string]int{}
+
+	for _, row := range rows {
+		if row.row != nil {
+			for idx, name := range row.row.columns {
+				if _, ok := known[name]; !ok {
+					known[name] = len(header)
+					header = append(header, field{Name: name})
+				}
+
+				hf := &header[known[name]]
+				if hf.Type == "" && row.row.values[idx] != nil {
+					hf.Type = formatOf(reflect.TypeOf(row.row.values[idx]))
+				}
+			}
+			continue
+		}
+
+		if row.columns != nil {
+			for _, column := range row.columns {
+				if _, ok := known[column.name]; ok {
+					continue
+				}
+
+				known[column.name] = len(header)
+				format := column.format
+				if format == "" {
+					format = formatOf(column.typ)
+				}
+
+				header = append(header, field{Name: column.name, Type: format})
+			}
+			continue
+		}
+
+		keys := row.value.MapKeys()
+		sort.Slice(keys, func(i, j int) bool {
+			return keys[i].String() < keys[j].String()
+		})
+
+		for _, key := range keys {
+			value := row.value.MapIndex(key)
+			for value.Kind() == reflect.Interface && !value.IsNil() {
+				value = value.Elem()
+			}
+
+			idx, ok := known[key.String()]
+			if !ok {
+				idx = len(header)
+				known[key.String()] = idx
+				header = append(header, field{Name: key.String()})
+			}
+
+			if header[idx].Type == "" && value.IsValid() && !(value.Kind() == reflect.Interface && value.IsNil()) {
+				header[idx].Type = formatOf(value.Type())
+			}
+		}
+	}
+
+	// columns of maps are sorted by name, even if the keys are spread over several rows
+	if len(rows) > 0 && rows[0].columns == nil && rows[0].row == nil {
+		sort.SliceStable(header, func(i, j int) bool {
+			return header[i].Name < header[j].Name
+		})
+	}
+
+	if len(rows) > 0 && rows[0].nested {
+		nestedHeader(header)
+	}
+
+	for idx := range header {
+		if header[idx].Type == "" {
+			// the column contains only nil values
+			header[idx].Type = "json"
+		}
+	}
+
+	return header
+}
+
+// nestedHeader sorts the columns of flattened maps by their header paths.
+func nestedHeader(header []field) {
+	paths := make(map[string][]pathElem, len(header))
+	for _, hf := range header {
+		// the names were built from valid keys, so they can be parsed
+		paths[hf.Name], _ = parsePath(hf.Name)
+	}
+
+	sort.SliceStable(header, func(i, j int) bool {
+		return lessPath(paths[header[i].Name], paths[header[j].Name])
+	})
+}
+
+// formatOf returns the type name of the type row for the go type.
+// Go types without a matching type name are encoded as json.
+func formatOf(t reflect.Type) string {
+	if format, ok := typeFormats[t]; ok {
+		// pointer types like *big.Int
+		return format
+	}
+
+	prefix := ""
+	if t.Kind() == reflect.Ptr {
+		prefix = "*"
+		t = t.Elem()
+	}
+
+	format, ok := typeFormats[t]
+	if !ok {
+		format = "json"
+	}
+
+	return prefix + format
+}
+
+// fromTyped is the inverse of toTyped and converts the value into its csv representation for the format.
+func (c *CSVParser) fromTyped(value interface{}, format string) (string, error) {
+	if _, _, ok := splitArraySeparator(format); ok {
+		parser, base, err := c.withArraySeparator(format)
+		if err != nil {
+			return "", err
+		}
+
+		return parser.fromTyped(value, base)
+	}
+
+	goType, ok := formatTypes[baseFormat(format)]
+	if !ok && format != "json" {
+		return "", fmt.Errorf("%w: %s", ErrUnsupportedType, format)
+	}
+
+	// dereference the value, unless the type itself is a pointer like *big.Int
+	rv := reflect.ValueOf(value)
+	for rv.Kind() == reflect.Ptr || rv.Kind() == reflect.Interface {
+		if rv.IsNil() {
+			return c.nullToken(), nil
+		}
+		if rv.Type() == goType {
+			break
+		}
+		rv = rv.Elem()
+	}
+	if !rv.IsValid() {
+		return c.nullToken(), nil
+	}
+
+	if format == "json" {
+		data, err := json.Marshal(rv.Interface())
+		if err != nil {
+			return "", fmt.Errorf("%w: %s", ErrInEmbeddedJSON, err)
+		}
+
+		return string(data), nil
+	}
+
+	if rv.Type() != goType {
+		return "", fmt.Errorf("%w: cannot encode %s as %q", ErrTypeMismatch, rv.Type(), format)
+	}
+
+	name, layout := splitFormat(strings.TrimSuffix(format, ",array"))
+
+	var values []string
+	if rv.Kind() != reflect.Slice {
+		values = []string{c.formatScalar(rv, name, layout)}
+	} else {
+		values = make([]string, rv.Len())
+		for i := range values {
+			values[i] = c.formatScalar(rv.Index(i), name, layout)
+		}
+	}
+
+	if name == "enum" {
+		for _, value := range values {
+			if err := checkEnum(value, layout); err != nil {
+				return "", err
+			}
+		}
+	}
+
+	if rv.Kind() != reflect.Slice {
+		return values[0], nil
+	}
+
+	return c.writeArray(values)
+}
+
+// formatScalar converts a string, number, big number, bool, time or duration value into its csv representation.
+func (c *CSVParser) formatScalar(rv reflect.Value, name, layout string) string {
+	if value, ok := c.formatTime(rv.Interface(), name, layout); ok {
+		return value
+	}
+	if value, ok := formatBig(rv.Interface()); ok {
+		return c.formatNumber(value)
+	}
+
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return c.formatNumber(strconv.FormatInt(rv.Int(), 10))
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return c.formatNumber(strconv.FormatUint(rv.Uint(), 10))
+	case reflect.Float32, reflect.Float64:
+		return c.formatNumber(strconv.FormatFloat(rv.Float(), 'f', -1, rv.Type().Bits()))
+	case reflect.Bool:
+		return c.formatBool(rv.Bool())
+	default:
+		return rv.String()
+	}
+}
+
+// writeArray joins the array values with the array separator, so that readArray can split them again.
+// Elements with surrounding spaces are quoted, since readArray trims unquoted elements.
+func (c *CSVParser) writeArray(values []string) (string, error) {
+	sep := c.arraySeparator()
+	if err := checkArraySeparator(sep); err != nil {
+		return "", err
+	}
+
+	var b strings.Builder
+	for idx, value := range values {
+		if idx > 0 {
+			b.WriteRune(sep)
+		}
+
+		if value != strings.TrimSpace(value) || strings.ContainsAny(value, "\"\r\n"+string(sep)) {
+			b.WriteString(`"` + strings.ReplaceAll(value, `"`, `""`) + `"`)
+			continue
+		}
+
+		b.WriteString(value)
+	}
+
+	return b.String(), nil
+}
@@ -0,0 +1,259 @@
+package csvx
+
+import (
+	"bytes"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+type encoderTarget struct {
+	Name    string                 `csv:"name"`
+	Counter *int64                 `csv:"counter"`
+	Score   float64                `csv:"score"`
+	Tags    []string               `csv:"tags"`
+	Flags   []bool                 `csv:"flags"`
+	Extra   map[string]interface{} `csv:"extra"`
+	Ignored string                 `csv:"-"`
+}
+
+func TestCSV_Marshal(t *testing.T) {
+	type args struct {
+		value interface{}
+		comma rune
+	}
+	tests := []struct {
+		name    string
+		args    args
+		want    string
+		wantErr error
+	}{
+		{
+			name: "test_maps",
+			args: args{
+				value: []map[string]interface{}{
+					{
+						"foo":     "first",
+						"bar":     int64(10),
+						"names":   []string{"hello", "world"},
+						"subtype": map[string]interface{}{"key": float64(10)},
+					},
+					{
+						"foo":     "second",
+						"bar":     int64(20),
+						"names":   []string{},
+						"subtype": nil,
+					},
+				},
+			},
+			want: "bar,foo,names,subtype\n" +
+				"int64,string,\"string,array\",json\n" +
+				"10,first,\"hello,world\",\"{\"\"key\"\":10}\"\n" +
+				"20,second,,\n",
+		},
+		{
+			name: "test_structs_with_semicolon",
+			args: args{
+				value: []encoderTarget{
+					{
+						Name:    "first",
+						Counter: func(i int64) *int64 { return &i }(10),
+						Score:   1.5,
+						Tags:    []string{"a", "b;c"},
+						Flags:   []bool{true, false},
+						Ignored: "ignored",
+					},
+				},
+				comma: ';',
+			},
+			want: "name;counter;score;tags;flags;extra\n" +
+				"string;*int64;float64;string,array;bool,array;json\n" +
+				"first;10;1.5;\"a;\"\"b;c\"\"\";\"true;false\";null\n",
+		},
+		{
+			name: "test_type_mismatch",
+			args: args{
+				value: []map[string]interface{}{
+					{"foo": "first"},
+					{"foo": int64(10)},
+				},
+			},
+			wantErr: ErrTypeMismatch,
+		},
+		{
+			name: "test_invalid_source",
+			args: args{
+				value: []string{"foo"},
+			},
+			wantErr: ErrInvalidMarshalSource,
+		},
+		{
+			name:    "test_nil",
+			args:    args{},
+			wantErr: ErrInvalidMarshalSource,
+		},
+		{
+			name: "test_empty_structs",
+			args: args{
+				value: []encoderTarget{},
+			},
+			want: "name,counter,score,tags,flags,extra\n" +
+				"string,*int64,float64,\"string,array\",\"bool,array\",json\n",
+		},
+		{
+			name: "test_empty_struct_pointers",
+			args: args{
+				value: &[]*encoderTarget{},
+			},
+			want: "name,counter,score,tags,flags,extra\n" +
+				"string,*int64,float64,\"string,array\",\"bool,array\",json\n",
+		},
+		{
+			name: "test_empty_maps",
+			args: args{
+				value: []map[string]interface{}{},
+			},
+			want: "",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			csv := CSVParser{Comma: tt.args.comma}
+
+			rslt, err := csv.Marshal(tt.args.value)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("TestMarshal() received error = %v, want %v", err, tt.wantErr)
+			}
+
+			if string(rslt) != tt.want {
+				t.Errorf("TestMarshal() is not equal. \ngot = %q\nwant = %q", rslt, tt.want)
+			}
+		})
+	}
+}
+
+func TestCSV_Marshal_roundTrip(t *testing.T) {
+	data := []map[string]interface{}{
+		{
+			"foo":     "first",
+			"bar":     int(10),
+			"ptr":     func(msg string) *string { return &msg }("pointer"),
+			"floats":  []float64{1.5, 2},
+			"ints":    &[]int64{1, 2},
+			"bools":   []bool{true, false},
+			"names":   []string{"hello, world", "how \"is\" it", " x", "y "},
+			"subtype": map[string]interface{}{"key": "a,b"},
+		},
+		{
+			"foo":     "second",
+			"bar":     int(20),
+			"ptr":     nil,
+			"floats":  []float64{},
+			"ints":    nil,
+			"bools":   []bool{},
+			"names":   []string{},
+			"subtype": []interface{}{float64(1), "two"},
+		},
+	}
+
+	for _, comma := range []rune{',', ';', '\t'} {
+		csv := CSVParser{Comma: comma}
+
+		encoded, err := csv.Marshal(data)
+		if err != nil {
+			t.Fatalf("TestMarshalRoundTrip() received error = %v", err)
+		}
+
+		rslt, err := csv.Typed(encoded)
+		if err != nil {
+			t.Fatalf("TestMarshalRoundTrip() received error = %v", err)
+		}
+
+		if !reflect.DeepEqual(rslt, data) {
+			t.Errorf("TestMarshalRoundTrip() is not equal. \ngot = %+#v\nwant = %+#v", rslt, data)
+		}
+	}
+}
+
+func TestCSV_Marshal_roundTripEmpty(t *testing.T) {
+	encoded, err := Marshal([]encoderTarget{})
+	if err != nil {
+		t.Fatalf("TestMarshalRoundTripEmpty() received error = %v", err)
+	}
+
+	var rslt []encoderTarget
+	if err := Unmarshal(encoded, &rslt); err != nil {
+		t.Fatalf("TestMarshalRoundTripEmpty() received error = %v", err)
+	}
+
+	if rslt == nil || len(rslt) != 0 {
+		t.Errorf("TestMarshalRoundTripEmpty() is not equal. \ngot = %+#v\nwant = %+#v", rslt, []encoderTarget{})
+	}
+}
+
+func TestCSV_Marshal_roundTripComment(t *testing.T) {
+	tests := []struct {
+		name string
+		data []map[string]interface{}
+	}{
+		{
+			name: "test_first_cell",
+			data: []map[string]interface{}{
+				{"a": "#x", "b": "y"},
+				{"a": "#\"quoted\", value", "b": ""},
+				{"a": "  #indented", "b": "#z"},
+			},
+		},
+		{
+			name: "test_single_column",
+			data: []map[string]interface{}{
+				{"a": "#x"},
+				{"a": "y"},
+			},
+		},
+		{
+			name: "test_header",
+			data: []map[string]interface{}{
+				{"#a": "#x", "b": "y"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			csv := CSVParser{}
+
+			encoded, err := csv.Marshal(tt.data)
+			if err != nil {
+				t.Fatalf("TestMarshalRoundTripComment() received error = %v", err)
+			}
+
+			rslt, err := csv.Typed(encoded)
+			if err != nil {
+				t.Fatalf("TestMarshalRoundTripComment() received error = %v", err)
+			}
+
+			if !reflect.DeepEqual(rslt, tt.data) {
+				t.Errorf("TestMarshalRoundTripComment() is not equal. \ngot = %+#v\nwant = %+#v", rslt, tt.data)
+			}
+		})
+	}
+}
+
+func TestEncoder_Encode(t *testing.T) {
+	var buf bytes.Buffer
+
+	enc := NewEncoder(&buf)
+	for _, row := range []map[string]interface{}{
+		{"foo": "first", "bar": true},
+		{"foo": "second", "bar": false, "unknown": "ignored"},
+	} {
+		if err := enc.Encode(row); err != nil {
+			t.Fatalf("TestEncoderEncode() received error = %v", err)
+		}
+	}
+
+	want := "bar,foo\nbool,string\ntrue,first\nfalse,second\n"
+	if buf.String() != want {
+		t.Errorf("TestEncoderEncode() is not equal. \ngot = %q\nwant = %q", buf.String(), want)
+	}
+}
@@ -0,0 +1,198 @@
+package csvx
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"unicode/utf16"
+	"unicode/utf8"
+)
+
+var ErrUnsupportedEncoding = errors.New("unsupported encoding")
+
+// Encoding defines the character encoding of the csv data.
+type Encoding int
+
+const (
+	// EncodingUTF8 reads the data as UTF-8. A byte order mark of UTF-8 is removed,
+	// data that starts with the byte order mark of UTF-16 is read as UTF-16.
+	EncodingUTF8 Encoding = iota
+	// EncodingLatin1 reads the data as ISO-8859-1.
+	EncodingLatin1
+	// EncodingWindows1252 reads the data as Windows-1252, the superset of ISO-8859-1 used by Windows.
+	EncodingWindows1252
+)
+
+var (
+	bomUTF8    = []byte{0xEF, 0xBB, 0xBF}
+	bomUTF16LE = []byte{0xFF, 0xFE}
+	bomUTF16BE = []byte{0xFE, 0xFF}
+)
+
+// windows1252 contains the characters of Windows-1252 that differ from ISO-8859-1, starting at 0x80.
+// Bytes that are undefined in Windows-1252 are read like in ISO-8859-1.
+var windows1252 = [32]rune{
+	'€', 0x81, '‚', 'ƒ', '„', '…', '†', '‡', 'ˆ', '‰', 'Š', '‹', 'Œ', 0x8D, 'Ž', 0x8F,
+	0x90, '‘', '’', '“', '”', '•', '–', '—', '˜', '™', 'š', '›', 'œ', 0x9D, 'ž', 'Ÿ',
+}
+
+// decodeReader returns a reader that converts the data of r from the configured encoding into UTF-8.
+//
+// A byte order mark at the start of the data takes precedence over the configured encoding and is removed.
+func (c *CSVParser) decodeReader(r io.Reader) io.Reader {
+	return &encodingReader{
+		src:      bufio.NewReader(r),
+		encoding: c.Encoding,
+	}
+}
+
+// encodingReader converts the data of src into UTF-8.
+// The encoding is detected when the data is read for the first time.
+type encodingReader struct {
+	src      *bufio.Reader
+	encoding Encoding
+	// next decodes the next character of src. It is nil until the encoding was detected.
+	next func(src *bufio.Reader) (rune, error)
+	// detected reports whether the encoding was detected already.
+	detected bool
+	// pending contains the encoded bytes of the last character that did not fit into the buffer.
+	pending []byte
+}
+
+// Read reads the converted data into p.
+func (e *encodingReader) Read(p []byte) (int, error) {
+	if !e.detected {
+		if err := e.detect(); err != nil {
+			return 0, err
+		}
+		e.detected = true
+	}
+
+	if e.next == nil {
+		return e.src.Read(p)
+	}
+
+	n := 0
+	for n < len(p) {
+		if len(e.pending) > 0 {
+			copied := copy(p[n:], e.pending)
+			e.pending = e.pending[copied:]
+			n += copied
+			continue
+		}
+
+		if n > 0 && e.src.Buffered() == 0 {
+			// do not block for more data, if some was read already
+			break
+		}
+
+		r, err := e.next(e.src)
+		if err != nil {
+			if n > 0 && err == io.EOF {
+				break
+			}
+			return n, err
+		}
+
+		var buf [utf8.UTFMax]byte
+		e.pending = append(e.pending[:0], buf[:utf8.EncodeRune(buf[:], r)]...)
+	}
+
+	return n, nil
+}
+
+// detect removes the byte order mark and selects the decoding function.
+func (e *encodingReader) detect() error {
+	head, _ := e.src.Peek(len(bomUTF8))
+	switch {
+	case bytes.HasPrefix(head, bomUTF8):
+		_, _ = e.src.Discard(len(bomUTF8))
+		return nil
+	case bytes.HasPrefix(head, bomUTF16LE):
+		_, _ = e.src.Discard(len(bomUTF16LE))
+		e.next = readUTF16(false)
+		return nil
+	case bytes.HasPrefix(head, bomUTF16BE):
+		_, _ = e.src.Discard(len(bomUTF16BE))
+		e.next = readUTF16(true)
+		return nil
+	}
+
+	switch e.encoding {
+	case EncodingUTF8:
+	case EncodingLatin1:
+		e.next = readLatin1
+	case EncodingWindows1252:
+		e.next = readWindows1252
+	default:
+		return fmt.Errorf("%w: %d", ErrUnsupportedEncoding, e.encoding)
+	}
+
+	return nil
+}
+
+// readLatin1 decodes a character of ISO-8859-1, where every byte is the code point of the character.
+func readLatin1(src *bufio.Reader) (rune, error) {
+	b, err := src.ReadByte()
+	if err != nil {
+		return 0, err
+	}
+
+	return rune(b), nil
+}
+
+// readWindows1252 decodes a character of Windows-1252.
+func readWindows1252(src *bufio.Reader) (rune, error) {
+	b, err := src.ReadByte()
+	if err != nil {
+		return 0, err
+	}
+
+	if b >= 0x80 && b < 0xA0 {
+		return windows1252[b-0x80], nil
+	}
+
+	return rune(b), nil
+}
+
+// readUTF16 returns a function that decodes a character of UTF-16 in little or big endian byte order.
+// Invalid surrogates and a trailing odd byte are decoded as utf8.RuneError.
+func readUTF16(bigEndian bool) func(src *bufio.Reader) (rune, error) {
+	unit := func(b []byte) rune {
+		if bigEndian {
+			return rune(b[0])<<8 | rune(b[1])
+		}
+		return rune(b[1])<<8 | rune(b[0])
+	}
+
+	return func(src *bufio.Reader) (rune, error) {
+		var b [2]byte
+		_, err := io.ReadFull(src, b[:])
+		if err == io.ErrUnexpectedEOF {
+			return utf8.RuneError, nil
+		}
+		if err != nil {
+			return 0, err
+		}
+
+		r := unit(b[:])
+		if !utf16.IsSurrogate(r) {
+			return r, nil
+		}
+
+		// the second half of a surrogate pair is only consumed if it is valid
+		low, err := src.Peek(2)
+		if err != nil {
+			return utf8.RuneError, nil
+		}
+
+		if decoded := utf16.DecodeRune(r, unit(low)); decoded != utf8.RuneError {
+			_, _ = src.Discard(2)
+			return decoded, nil
+		}
+
+		return utf8.RuneError, nil
+	}
+}
@@ -0,0 +1,146 @@
+package csvx
+
+import (
+	"errors"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+	"unicode/utf16"
+)
+
+// encodeUTF16 encodes s as UTF-16 with byte order mark.
+func encodeUTF16(s string, bigEndian bool) []byte {
+	data := []byte{0xFF, 0xFE}
+	if bigEndian {
+		data = []byte{0xFE, 0xFF}
+	}
+
+	for _, unit := range utf16.Encode([]rune(s)) {
+		if bigEndian {
+			data = append(data, byte(unit>>8), byte(unit))
+		} else {
+			data = append(data, byte(unit), byte(unit>>8))
+		}
+	}
+
+	return data
+}
+
+func TestCSV_Typed_encoding(t *testing.T) {
+	type args struct {
+		data     []byte
+		encoding Encoding
+	}
+	tests := []struct {
+		name    string
+		args    args
+		want    []map[string]interface{}
+		wantErr error
+	}{
+		{
+			name: "test_utf8",
+			args: args{data: []byte("name,price\nstring,float64\nMüller,1.5\n")},
+			want: []map[string]interface{}{{"name": "Müller", "price": 1.5}},
+		},
+		{
+			name: "test_utf8_bom",
+			args: args{data: []byte("\xEF\xBB\xBFname,price\nstring,float64\nMüller,1.5\n")},
+			want: []map[string]interface{}{{"name": "Müller", "price": 1.5}},
+		},
+		{
+			name: "test_utf16le",
+			args: args{data: encodeUTF16("name,price\r\nstring,float64\r\nMüller 𝄞,1.5\r\n", false)},
+			want: []map[string]interface{}{{"name": "Müller 𝄞", "price": 1.5}},
+		},
+		{
+			name: "test_utf16be",
+			args: args{data: encodeUTF16("name,price\nstring,float64\nMüller,1.5", true)},
+			want: []map[string]interface{}{{"name": "Müller", "price": 1.5}},
+		},
+		{
+			name: "test_utf16_bom_precedence",
+			args: args{data: encodeUTF16("name\nstring\nMüller", false), encoding: EncodingLatin1},
+			want: []map[string]interface{}{{"name": "Müller"}},
+		},
+		{
+			name: "test_latin1",
+			args: args{data: []byte("name,price\nstring,float64\nM\xFCller \x80,1.5\n"), encoding: EncodingLatin1},
+			want: []map[string]interface{}{{"name": "Müller \u0080", "price": 1.5}},
+		},
+		{
+			name: "test_windows1252",
+			args: args{data: []byte("name,price\nstring,float64\nM\xFCller \x80\x93\x81,1.5\n"), encoding: EncodingWindows1252},
+			want: []map[string]interface{}{{"name": "Müller €“\u0081", "price": 1.5}},
+		},
+		{
+			name:    "test_unsupported",
+			args:    args{data: []byte("name\nstring\nfirst\n"), encoding: Encoding(10)},
+			wantErr: ErrUnsupportedEncoding,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			csv := New(WithEncoding(tt.args.encoding))
+
+			rslt, err := csv.Typed(tt.args.data)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("TestTypedEncoding() received error = %v, want %v", err, tt.wantErr)
+			}
+
+			if !reflect.DeepEqual(rslt, tt.want) {
+				t.Errorf("TestTypedEncoding() is not equal. \ngot = %+#v\nwant = %+#v", rslt, tt.want)
+			}
+		})
+	}
+}
+
+func TestEncodingReader(t *testing.T) {
+	tests := []struct {
+		name     string
+		data     []byte
+		encoding Encoding
+		want     string
+	}{
+		{
+			name: "test_empty",
+			data: []byte{},
+			want: "",
+		},
+		{
+			name: "test_bom_only",
+			data: []byte{0xEF, 0xBB, 0xBF},
+			want: "",
+		},
+		{
+			name: "test_utf16_odd_byte",
+			data: []byte{0xFF, 0xFE, 'a', 0, 'b'},
+			want: "a�",
+		},
+		{
+			name: "test_utf16_unpaired_surrogate",
+			data: []byte{0xFF, 0xFE, 0x34, 0xD8, 'a', 0},
+			want: "�a",
+		},
+		{
+			name:     "test_long_windows1252",
+			data:     []byte(strings.Repeat("\x80", 5000)),
+			encoding: EncodingWindows1252,
+			want:     strings.Repeat("€", 5000),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			csv := CSVParser{Encoding: tt.encoding}
+
+			rslt, err := io.ReadAll(csv.decodeReader(strings.NewReader(string(tt.data))))
+			if err != nil {
+				t.Fatalf("TestEncodingReader() received error = %v", err)
+			}
+
+			if string(rslt) != tt.want {
+				t.Errorf("TestEncodingReader() is not equal. \ngot = %q\nwant = %q", rslt, tt.want)
+			}
+		})
+	}
+}
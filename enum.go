@@ -0,0 +1,64 @@
+package csvx
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+var ErrInvalidEnumValue = errors.New("value is not part of the enum")
+
+// enumType returns the conversion of enum types like "enum(draft|published|archived)",
+// whose values are strings. Empty cells are not checked, they are read as empty string or nil.
+func enumType(name, values string) (TypeFunc, reflect.Type, bool) {
+	if name != "enum" {
+		return nil, nil, false
+	}
+
+	fn := func(value string) (interface{}, error) {
+		if err := checkEnum(value, values); err != nil {
+			return nil, err
+		}
+
+		return value, nil
+	}
+
+	return fn, reflect.TypeOf(""), true
+}
+
+// checkEnums checks that the enum types of the type row list their values, so that a type like "enum()"
+// is rejected once when the header is read, even if the cells of the column are empty.
+func checkEnums(headerInfo map[int]field) error {
+	for idx := 0; idx < len(headerInfo); idx++ {
+		hf := headerInfo[idx]
+
+		typ, _, _ := splitDefault(hf.Type)
+		format, _, _ := splitArraySeparator(strings.TrimPrefix(typ, "*"))
+		name, values := splitFormat(strings.TrimSuffix(format, ",array"))
+		if name == "enum" && values == "" {
+			return fmt.Errorf("%w: enum without values for column %q in column %d", ErrUnsupportedType, hf.Name, idx+1)
+		}
+	}
+
+	return nil
+}
+
+// checkEnum checks whether the value is one of the values separated by "|".
+func checkEnum(value, values string) error {
+	if value == "" {
+		return nil
+	}
+
+	if values == "" {
+		return fmt.Errorf("%w: enum without values", ErrUnsupportedType)
+	}
+
+	for _, allowed := range strings.Split(values, "|") {
+		if value == allowed {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("%w: %q is not one of %s", ErrInvalidEnumValue, value, values)
+}
@@ -0,0 +1,167 @@
+package csvx
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestCSV_toTyped_enum(t *testing.T) {
+	type args struct {
+		value, format string
+		isPointerType bool
+	}
+	tests := []struct {
+		name    string
+		args    args
+		want    interface{}
+		wantErr error
+	}{
+		{
+			name: "test_enum",
+			args: args{value: "draft", format: "enum(draft|published|archived)"},
+			want: "draft",
+		},
+		{
+			name:    "test_enum_invalid",
+			args:    args{value: "drafts", format: "enum(draft|published|archived)"},
+			wantErr: ErrInvalidEnumValue,
+		},
+		{
+			name: "test_enum_empty",
+			args: args{value: "", format: "enum(draft|published)"},
+			want: "",
+		},
+		{
+			name:    "test_enum_without_values",
+			args:    args{value: "draft", format: "enum()"},
+			wantErr: ErrUnsupportedType,
+		},
+		{
+			name: "test_enum_ptr",
+			args: args{value: "published", format: "enum(draft|published)", isPointerType: true},
+			want: func(s string) *string { return &s }("published"),
+		},
+		{
+			name: "test_enum_ptr_empty",
+			args: args{value: "", format: "enum(draft|published)", isPointerType: true},
+			want: nil,
+		},
+		{
+			name: "test_enum_array",
+			args: args{value: "draft, published", format: "enum(draft|published),array"},
+			want: []string{"draft", "published"},
+		},
+		{
+			name:    "test_enum_array_invalid",
+			args:    args{value: "draft,deleted", format: "enum(draft|published),array"},
+			wantErr: ErrInvalidEnumValue,
+		},
+		{
+			name: "test_enum_array_ptr",
+			args: args{value: "draft", format: "enum(draft|published),array", isPointerType: true},
+			want: &[]string{"draft"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			csv := CSVParser{Comma: ',', Comment: '#', TrimLeadingSpace: true}
+
+			rslt, err := csv.toTyped(tt.args.value, tt.args.format, tt.args.isPointerType)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("TestToTypedEnum() received error = %v, want %v", err, tt.wantErr)
+			}
+
+			if !reflect.DeepEqual(rslt, tt.want) {
+				t.Errorf("TestToTypedEnum() is not equal. \ngot = %+#v\nwant = %+#v", rslt, tt.want)
+			}
+		})
+	}
+}
+
+func TestCSV_Typed_enumWithoutValues(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    []byte
+		wantErr error
+	}{
+		{
+			name:    "test_empty_cells",
+			data:    []byte("id,status\nint64,enum()\n1,\n2,\n"),
+			wantErr: ErrUnsupportedType,
+		},
+		{
+			name:    "test_without_rows",
+			data:    []byte("id,status\nint64,*enum\n"),
+			wantErr: ErrUnsupportedType,
+		},
+		{
+			name:    "test_array_separator",
+			data:    []byte("id,status\nint64,\"enum(),array(|)\"\n1,\n"),
+			wantErr: ErrUnsupportedType,
+		},
+		{
+			name:    "test_default",
+			data:    []byte("id,status\nint64,enum()=\n1,\n"),
+			wantErr: ErrUnsupportedType,
+		},
+		{
+			name: "test_with_values",
+			data: []byte("id,status\nint64,enum(draft)\n1,\n"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			csv := New()
+
+			_, err := csv.Typed(tt.data)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("TestTypedEnumWithoutValues() received error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestCSV_Marshal_enum(t *testing.T) {
+	type post struct {
+		Title  string   `csv:"title"`
+		Status string   `csv:"status,enum(draft|published)"`
+		Labels []string `csv:"labels,enum(a|b),array"`
+		Review *string  `csv:"review,*enum(open|done)"`
+	}
+
+	csv := New()
+
+	data := []post{{Title: "first", Status: "draft", Labels: []string{"a", "b"}}}
+	encoded, err := csv.Marshal(data)
+	if err != nil {
+		t.Fatalf("TestMarshalEnum() received error = %v", err)
+	}
+
+	want := "title,status,labels,review\n" +
+		"string,enum(draft|published),\"enum(a|b),array\",*enum(open|done)\n" +
+		"first,draft,\"a,b\",\n"
+	if string(encoded) != want {
+		t.Errorf("TestMarshalEnum() is not equal. \ngot = %q\nwant = %q", encoded, want)
+	}
+
+	var rslt []post
+	err = csv.Unmarshal(encoded, &rslt)
+	if err != nil {
+		t.Fatalf("TestMarshalEnum() received error = %v", err)
+	}
+
+	if !reflect.DeepEqual(rslt, data) {
+		t.Errorf("TestMarshalEnum() is not equal. \ngot = %+#v\nwant = %+#v", rslt, data)
+	}
+
+	for _, invalid := range []post{
+		{Status: "deleted"},
+		{Status: "draft", Labels: []string{"c"}},
+	} {
+		_, err = csv.Marshal(invalid)
+		if !errors.Is(err, ErrInvalidEnumValue) {
+			t.Errorf("TestMarshalEnum() received error = %v, want %v", err, ErrInvalidEnumValue)
+		}
+	}
+}
@@ -0,0 +1,54 @@
+package csvx
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ParseError describes a cell that could not be converted into the type declared in the type row,
+// or a row that does not match the header.
+//
+// It wraps the underlying error, so errors.Is and errors.As can be used to check for the
+// sentinel errors of this package or errors like strconv.ErrSyntax.
+type ParseError struct {
+	// Line is the source line on which the row starts, beginning at 1.
+	Line int
+	// Column is the position of the cell within the row, beginning at 1.
+	Column int
+	// Name is the header name of the column.
+	Name string
+	// Type is the type of the column as declared in the type row.
+	Type string
+	// Value is the raw value of the cell.
+	Value string
+	// Err is the underlying error.
+	Err error
+}
+
+// Error returns the error message including the position of the cell.
+func (e *ParseError) Error() string {
+	if e.Name == "" && e.Type == "" && e.Value == "" {
+		// the error concerns the row and not the value of a cell, like ErrRecordWidth
+		return fmt.Sprintf("line %d, column %d: %v", e.Line, e.Column, e.Err)
+	}
+
+	return fmt.Sprintf("line %d, column %d (%q of type %q): invalid value %q: %v", e.Line, e.Column, e.Name, e.Type, e.Value, e.Err)
+}
+
+// Unwrap returns the underlying error.
+func (e *ParseError) Unwrap() error {
+	return e.Err
+}
+
+// ParseErrors is a list of cell errors, as returned by Validate.
+type ParseErrors []*ParseError
+
+// Error returns the messages of all errors, one per line.
+func (e ParseErrors) Error() string {
+	msgs := make([]string, len(e))
+	for idx, err := range e {
+		msgs[idx] = err.Error()
+	}
+
+	return fmt.Sprintf("%d invalid values:\n%s", len(e), strings.Join(msgs, "\n"))
+}
@@ -0,0 +1,113 @@
+package csvx
+
+import (
+	"errors"
+	"reflect"
+	"strconv"
+	"testing"
+)
+
+func TestCSV_Typed_ParseError(t *testing.T) {
+	type args struct {
+		data []byte
+	}
+	tests := []struct {
+		name    string
+		args    args
+		want    *ParseError
+		wantErr error
+	}{
+		{
+			name: "test_invalid_int64",
+			args: args{
+				data: []byte("foo,bar\nstring,int64\nfirst,10\nsecond,abc\n"),
+			},
+			want: &ParseError{
+				Line:   4,
+				Column: 2,
+				Name:   "bar",
+				Type:   "int64",
+				Value:  "abc",
+			},
+			wantErr: strconv.ErrSyntax,
+		},
+		{
+			name: "test_invalid_after_blank_lines_and_comments",
+			args: args{
+				data: []byte("foo,bar\n*string,*int\n\n# comment\nfirst,10\n\n\nsecond,1.5"),
+			},
+			want: &ParseError{
+				Line:   8,
+				Column: 2,
+				Name:   "bar",
+				Type:   "*int",
+				Value:  "1.5",
+			},
+			wantErr: strconv.ErrSyntax,
+		},
+		{
+			name: "test_invalid_after_multiline_value",
+			args: args{
+				data: []byte("foo,bar\nstring,json\n\"first\nsecond\r\nthird\",{}\n\"fourth\nfifth\",{\n"),
+			},
+			want: &ParseError{
+				Line:   6,
+				Column: 2,
+				Name:   "bar",
+				Type:   "json",
+				Value:  "{",
+			},
+			wantErr: ErrInEmbeddedJSON,
+		},
+		{
+			name: "test_unsupported_type",
+			args: args{
+				data: []byte("foo,bar\nstring,unknown\nfirst,second"),
+			},
+			want: &ParseError{
+				Line:   3,
+				Column: 2,
+				Name:   "bar",
+				Type:   "unknown",
+				Value:  "second",
+			},
+			wantErr: ErrUnsupportedType,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			csv := CSVParser{Comma: ',', Comment: '#'}
+
+			_, err := csv.Typed(tt.args.data)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("TestTypedParseError() received error = %v, want %v", err, tt.wantErr)
+			}
+
+			var parseErr *ParseError
+			if !errors.As(err, &parseErr) {
+				t.Fatalf("TestTypedParseError() received no *ParseError: %v", err)
+			}
+
+			tt.want.Err = parseErr.Err
+			if !reflect.DeepEqual(parseErr, tt.want) {
+				t.Errorf("TestTypedParseError() is not equal. \ngot = %+#v\nwant = %+#v", parseErr, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseError_Error(t *testing.T) {
+	err := &ParseError{
+		Line:   3,
+		Column: 2,
+		Name:   "bar",
+		Type:   "int64",
+		Value:  "abc",
+		Err:    ErrUnsupportedType,
+	}
+
+	want := `line 3, column 2 ("bar" of type "int64"): invalid value "abc": unsupported type format type`
+	if err.Error() != want {
+		t.Errorf("TestParseErrorError() is not equal. \ngot = %s\nwant = %s", err.Error(), want)
+	}
+}
@@ -0,0 +1,127 @@
+package csvx
+
+import (
+	"errors"
+	"fmt"
+)
+
+var (
+	ErrDuplicateHeader = errors.New("duplicate header name")
+	ErrEmptyHeader     = errors.New("empty header name")
+	ErrInvalidDefault  = errors.New("invalid default value")
+)
+
+// DuplicateHeaderPolicy defines how columns with the same header name are handled.
+type DuplicateHeaderPolicy int
+
+const (
+	// DuplicateHeaderOverwrite lets the value of a later column overwrite the value of an earlier column with the same name.
+	DuplicateHeaderOverwrite DuplicateHeaderPolicy = iota
+	// DuplicateHeaderError rejects the csv with ErrDuplicateHeader.
+	DuplicateHeaderError
+	// DuplicateHeaderSuffix renames the later columns by appending a counter, e.g. "name_2" and "name_3".
+	DuplicateHeaderSuffix
+	// DuplicateHeaderCollect collects the values of all columns with the same name into a []interface{}.
+	DuplicateHeaderCollect
+)
+
+// checkHeader checks the header names for blank and duplicate names and applies the duplicate header policy.
+//
+// Columns that are skipped, because they have no type and SkipEmptyColumns is set, are not checked.
+func (c *CSVParser) checkHeader(headerInfo map[int]field) (map[int]field, error) {
+	names := map[string]int{}
+	for idx := 0; idx < len(headerInfo); idx++ {
+		if c.isTyped && headerInfo[idx].Type == "" && c.SkipEmptyColumns {
+			continue
+		}
+
+		names[headerInfo[idx].Name]++
+	}
+
+	seen := map[string]int{}
+	for idx := 0; idx < len(headerInfo); idx++ {
+		hf := headerInfo[idx]
+		if c.isTyped && hf.Type == "" && c.SkipEmptyColumns {
+			continue
+		}
+
+		if hf.Name == "" && c.RejectEmptyHeaders {
+			return nil, fmt.Errorf("%w in column %d", ErrEmptyHeader, idx+1)
+		}
+
+		if c.OverflowKey != "" && hf.Name == c.OverflowKey {
+			return nil, fmt.Errorf("%w: %q in column %d is used as overflow key", ErrDuplicateHeader, hf.Name, idx+1)
+		}
+
+		if names[hf.Name] < 2 {
+			continue
+		}
+
+		seen[hf.Name]++
+		switch c.DuplicateHeaders {
+		case DuplicateHeaderError:
+			return nil, fmt.Errorf("%w: %q in column %d", ErrDuplicateHeader, hf.Name, idx+1)
+		case DuplicateHeaderSuffix:
+			if seen[hf.Name] == 1 {
+				continue
+			}
+
+			// find a suffix that is not used by another column
+			suffix := seen[hf.Name]
+			for names[fmt.Sprintf("%s_%d", hf.Name, suffix)] > 0 {
+				suffix++
+			}
+
+			hf.Name = fmt.Sprintf("%s_%d", hf.Name, suffix)
+			names[hf.Name]++
+		case DuplicateHeaderCollect:
+			hf.Collect = true
+		}
+
+		headerInfo[idx] = hf
+	}
+
+	return headerInfo, nil
+}
+
+// checkDefaults splits the default values from the types of the type row, like "int64=10" or "*string=unknown",
+// and checks that they can be converted into the type.
+func (c *CSVParser) checkDefaults(headerInfo map[int]field) (map[int]field, error) {
+	for idx := 0; idx < len(headerInfo); idx++ {
+		hf := headerInfo[idx]
+
+		typ, value, ok := splitDefault(hf.Type)
+		if !ok {
+			continue
+		}
+
+		if _, err := c.convertCell(value, typ); err != nil {
+			return nil, fmt.Errorf("%w %q for column %q in column %d: %s", ErrInvalidDefault, value, hf.Name, idx+1, err)
+		}
+
+		hf.Type, hf.Default, hf.HasDefault = typ, value, true
+		headerInfo[idx] = hf
+	}
+
+	return headerInfo, nil
+}
+
+// splitDefault splits a type like "int64=10" into the type and the default value.
+// A "=" within the arguments of the type, like in "time(a=b)", does not start the default value.
+func splitDefault(typ string) (string, string, bool) {
+	depth := 0
+	for idx, r := range typ {
+		switch r {
+		case '(':
+			depth++
+		case ')':
+			depth--
+		case '=':
+			if depth == 0 {
+				return typ[:idx], typ[idx+1:], true
+			}
+		}
+	}
+
+	return typ, "", false
+}
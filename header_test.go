@@ -0,0 +1,258 @@
+package csvx
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestCSV_checkHeader(t *testing.T) {
+	type args struct {
+		data             []byte
+		policy           DuplicateHeaderPolicy
+		rejectEmpty      bool
+		skipEmptyColumns bool
+	}
+	tests := []struct {
+		name    string
+		args    args
+		want    []map[string]interface{}
+		wantErr error
+	}{
+		{
+			name: "test_overwrite",
+			args: args{
+				data:   []byte("name,name\nstring,int\nfirst,10"),
+				policy: DuplicateHeaderOverwrite,
+			},
+			want: []map[string]interface{}{
+				{"name": 10},
+			},
+		},
+		{
+			name: "test_error",
+			args: args{
+				data:   []byte("name,other,name\nstring,string,int\nfirst,second,10"),
+				policy: DuplicateHeaderError,
+			},
+			wantErr: ErrDuplicateHeader,
+		},
+		{
+			name: "test_suffix",
+			args: args{
+				data:   []byte("name,name_2,name,name\nstring,string,int,bool\nfirst,second,10,true"),
+				policy: DuplicateHeaderSuffix,
+			},
+			want: []map[string]interface{}{
+				{"name": "first", "name_2": "second", "name_3": 10, "name_4": true},
+			},
+		},
+		{
+			name: "test_collect",
+			args: args{
+				data:   []byte("tag,other,tag,tag\nstring,string,int,string\nfirst,second,10\n,third,,"),
+				policy: DuplicateHeaderCollect,
+			},
+			want: []map[string]interface{}{
+				{"tag": []interface{}{"first", 10}, "other": "second"},
+				{"tag": []interface{}{"", 0, ""}, "other": "third"},
+			},
+		},
+		{
+			name: "test_skipped_duplicates",
+			args: args{
+				data:             []byte("name,,\nstring,,\nfirst,second,third"),
+				policy:           DuplicateHeaderError,
+				rejectEmpty:      true,
+				skipEmptyColumns: true,
+			},
+			want: []map[string]interface{}{
+				{"name": "first"},
+			},
+		},
+		{
+			name: "test_empty_allowed",
+			args: args{
+				data: []byte("name,\nstring,string\nfirst,second"),
+			},
+			want: []map[string]interface{}{
+				{"name": "first", "": "second"},
+			},
+		},
+		{
+			name: "test_empty_rejected",
+			args: args{
+				data:        []byte("name,\nstring,string\nfirst,second"),
+				rejectEmpty: true,
+			},
+			wantErr: ErrEmptyHeader,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			csv := New(
+				WithDuplicateHeaders(tt.args.policy),
+				WithRejectEmptyHeaders(tt.args.rejectEmpty),
+				WithSkipEmptyColumns(tt.args.skipEmptyColumns),
+			)
+
+			rslt, err := csv.Typed(tt.args.data)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("TestCheckHeader() received error = %v, want %v", err, tt.wantErr)
+			}
+
+			if !reflect.DeepEqual(rslt, tt.want) {
+				t.Errorf("TestCheckHeader() is not equal. \ngot = %+#v\nwant = %+#v", rslt, tt.want)
+			}
+		})
+	}
+}
+
+func TestCSV_checkHeader_untyped(t *testing.T) {
+	csv := New(WithDuplicateHeaders(DuplicateHeaderSuffix))
+
+	rows, err := csv.UntypedRows([]byte("a,b,a\n1,2,3"))
+	if err != nil {
+		t.Fatalf("TestCheckHeaderUntyped() received error = %v", err)
+	}
+
+	want := []string{"a", "b", "a_2"}
+	if !reflect.DeepEqual(rows[0].Columns(), want) {
+		t.Errorf("TestCheckHeaderUntyped() is not equal. \ngot = %+#v\nwant = %+#v", rows[0].Columns(), want)
+	}
+}
+
+func TestCSV_checkHeader_Unmarshal(t *testing.T) {
+	type entry struct {
+		Tags []int64 `csv:"tag"`
+	}
+
+	csv := New(WithDuplicateHeaders(DuplicateHeaderCollect))
+
+	var rslt []entry
+	err := csv.Unmarshal([]byte("tag,tag\nint64,int64\n1,2"), &rslt)
+	if err != nil {
+		t.Fatalf("TestCheckHeaderUnmarshal() received error = %v", err)
+	}
+
+	want := []entry{{Tags: []int64{1, 2}}}
+	if !reflect.DeepEqual(rslt, want) {
+		t.Errorf("TestCheckHeaderUnmarshal() is not equal. \ngot = %+#v\nwant = %+#v", rslt, want)
+	}
+}
+
+func TestCSV_checkHeader_UnmarshalError(t *testing.T) {
+	type entry struct {
+		A []float64 `csv:"a"`
+	}
+
+	tests := []struct {
+		name       string
+		data       []byte
+		wantLine   int
+		wantColumn int
+		wantValue  string
+	}{
+		{
+			name:       "test_short_row",
+			data:       []byte("a,b,a\njson,string,json\n1,x,2\n[1]\n"),
+			wantLine:   4,
+			wantColumn: 1,
+			wantValue:  "[1]",
+		},
+		{
+			name:       "test_last_duplicate",
+			data:       []byte("a,b,a\njson,string,json\n1,x,\"[2]\"\n"),
+			wantLine:   3,
+			wantColumn: 3,
+			wantValue:  "[2]",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			csv := New(WithDuplicateHeaders(DuplicateHeaderCollect))
+
+			var rslt []entry
+			err := csv.Unmarshal(tt.data, &rslt)
+
+			var parseErr *ParseError
+			if !errors.As(err, &parseErr) || !errors.Is(err, ErrInEmbeddedJSON) {
+				t.Fatalf("TestCheckHeaderUnmarshalError() received error = %v, want %v", err, ErrInEmbeddedJSON)
+			}
+
+			if parseErr.Line != tt.wantLine || parseErr.Column != tt.wantColumn || parseErr.Value != tt.wantValue {
+				t.Errorf("TestCheckHeaderUnmarshalError() = line %d, column %d, value %q, want line %d, column %d, value %q",
+					parseErr.Line, parseErr.Column, parseErr.Value, tt.wantLine, tt.wantColumn, tt.wantValue)
+			}
+		})
+	}
+}
+
+func TestCSV_checkDefaults(t *testing.T) {
+	unknown := "unknown"
+	first := "first"
+
+	tests := []struct {
+		name    string
+		data    []byte
+		want    []map[string]interface{}
+		wantErr error
+	}{
+		{
+			name: "test_defaults",
+			data: []byte("id,name,tags,at,count\n" +
+				"int64=10,*string=unknown,\"string,array=a,b\",time(2006-01-02)=2021-03-04,int\n" +
+				",,,,5\n" +
+				"1,first,c,2021-03-05,2\n"),
+			want: []map[string]interface{}{
+				{"id": int64(10), "name": &unknown, "tags": []string{"a", "b"}, "at": time.Date(2021, 3, 4, 0, 0, 0, 0, time.UTC), "count": 5},
+				{"id": int64(1), "name": &first, "tags": []string{"c"}, "at": time.Date(2021, 3, 5, 0, 0, 0, 0, time.UTC), "count": 2},
+			},
+		},
+		{
+			name:    "test_invalid_default",
+			data:    []byte("id\nint64=ten\n1\n"),
+			wantErr: ErrInvalidDefault,
+		},
+		{
+			name:    "test_invalid_default_without_rows",
+			data:    []byte("id,name\nstring,bool=maybe\n"),
+			wantErr: ErrInvalidDefault,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			csv := New()
+
+			rslt, err := csv.Typed(tt.data)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("TestCheckDefaults() received error = %v, want %v", err, tt.wantErr)
+			}
+
+			if !reflect.DeepEqual(rslt, tt.want) {
+				t.Errorf("TestCheckDefaults() is not equal. \ngot = %+#v\nwant = %+#v", rslt, tt.want)
+			}
+		})
+	}
+}
+
+func TestSplitDefault(t *testing.T) {
+	tests := []struct {
+		typ, wantType, wantValue string
+		wantOk                   bool
+	}{
+		{typ: "int64", wantType: "int64"},
+		{typ: "int64=10", wantType: "int64", wantValue: "10", wantOk: true},
+		{typ: "*string=a=b", wantType: "*string", wantValue: "a=b", wantOk: true},
+		{typ: "string=", wantType: "string", wantOk: true},
+		{typ: "time(a=b)", wantType: "time(a=b)"},
+		{typ: "time(a=b)=c", wantType: "time(a=b)", wantValue: "c", wantOk: true},
+	}
+	for _, tt := range tests {
+		typ, value, ok := splitDefault(tt.typ)
+		if typ != tt.wantType || value != tt.wantValue || ok != tt.wantOk {
+			t.Errorf("TestSplitDefault(%q) = %q, %q, %v, want %q, %q, %v", tt.typ, typ, value, ok, tt.wantType, tt.wantValue, tt.wantOk)
+		}
+	}
+}
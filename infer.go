@@ -0,0 +1,126 @@
+package csvx
+
+import (
+	"bytes"
+	"encoding/json"
+	"strconv"
+	"strings"
+)
+
+// InferTypes examines the data rows of untyped csv using the default settings and proposes the type row.
+//
+// See CSVParser.InferTypes for details.
+func InferTypes(data []byte) ([]string, error) {
+	return (&CSVParser{}).InferTypes(data)
+}
+
+// InferTypes examines the data rows of untyped csv and proposes a type for each column of the header.
+//
+// Columns are typed as int64, float64 or bool, if all values can be parsed as such, as json if all values are
+// json objects and as "string,array" if values contain the array separator. All other columns are typed as string.
+// Columns with empty or missing values get a pointer type, so that these values are read as nil,
+// except for json and arrays. Columns without any value are typed as *string.
+//
+// If SampleRows is set, only the first rows are examined.
+func (c *CSVParser) InferTypes(data []byte) ([]string, error) {
+	dec := c.UntypedDecoder(bytes.NewReader(data))
+	if !dec.ensureHeader() {
+		return nil, dec.Err()
+	}
+
+	types := make([]string, len(dec.headerInfo))
+	sparse := make([]bool, len(types))
+	for rows := 0; (c.SampleRows <= 0 || rows < c.SampleRows) && dec.Next(); rows++ {
+		for idx := range types {
+			value := ""
+			if idx < len(dec.record) {
+				value = dec.record[idx]
+			}
+
+			if value == "" || dec.parser.isNull(value) {
+				sparse[idx] = true
+				continue
+			}
+
+			types[idx] = mergeTypes(types[idx], dec.parser.inferType(value))
+		}
+	}
+	if err := dec.Err(); err != nil {
+		return nil, err
+	}
+
+	for idx, typ := range types {
+		switch {
+		case typ == "":
+			// the column has no values
+			types[idx] = "*string"
+		case sparse[idx] && typ != "json" && typ != "string,array":
+			types[idx] = "*" + typ
+		}
+	}
+
+	return types, nil
+}
+
+// TypedFromInferred unmarshals the untyped data into a slice of map[string]interface{}, with the types
+// proposed by InferTypes.
+func (c *CSVParser) TypedFromInferred(data []byte) ([]map[string]interface{}, error) {
+	types, err := c.InferTypes(data)
+	if err != nil {
+		return nil, err
+	}
+
+	dec := c.newDecoder(bytes.NewReader(data), true)
+	dec.types = types
+
+	return c.decodeAll(dec)
+}
+
+// inferType returns the type of a single non-empty value.
+func (c *CSVParser) inferType(value string) string {
+	if strings.HasPrefix(value, "{") && json.Valid([]byte(value)) {
+		return "json"
+	}
+
+	if strings.ContainsRune(value, c.arraySeparator()) {
+		// the value must be readable as a single array
+		records, err := c.readArray(value)
+		if err == nil && len(records) == 1 {
+			return "string,array"
+		}
+
+		return "string"
+	}
+
+	if num, err := c.normalizeNumber(value); err == nil {
+		if _, err := strconv.ParseInt(num, 10, 64); err == nil {
+			return "int64"
+		}
+
+		// strconv.ParseFloat accepts "nan" and "inf", which are more likely words than numbers
+		if _, err := strconv.ParseFloat(num, 64); err == nil && strings.ContainsAny(num, "0123456789") {
+			return "float64"
+		}
+	}
+
+	if _, err := c.parseBool(value); err == nil {
+		return "bool"
+	}
+
+	return "string"
+}
+
+// mergeTypes returns the type that can hold the values of both types.
+func mergeTypes(a, b string) string {
+	switch {
+	case a == "" || a == b:
+		return b
+	case (a == "int64" && b == "float64") || (a == "float64" && b == "int64"):
+		return "float64"
+	case (a == "string,array" && b != "json") || (b == "string,array" && a != "json"):
+		// values without separator are arrays with a single element
+		return "string,array"
+	default:
+		return "string"
+	}
+}
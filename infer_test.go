@@ -0,0 +1,111 @@
+package csvx
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestCSV_InferTypes(t *testing.T) {
+	type args struct {
+		data       []byte
+		sampleRows int
+	}
+	tests := []struct {
+		name    string
+		args    args
+		want    []string
+		wantErr bool
+	}{
+		{
+			name: "test_types",
+			args: args{
+				data: []byte("id,price,active,name,tags,extra\n" +
+					"1,10,true,first,\"a,b\",\"{\"\"a\"\": 1}\"\n" +
+					"2,10.5,false,second,c,{}\n"),
+			},
+			want: []string{"int64", "float64", "bool", "string", "string,array", "json"},
+		},
+		{
+			name: "test_sparse",
+			args: args{
+				data: []byte("id,comment,tags,empty\n1,,\"a,b\"\n,first\n"),
+			},
+			want: []string{"*int64", "*string", "string,array", "*string"},
+		},
+		{
+			name: "test_mixed",
+			args: args{
+				data: []byte("a,b,c,d\n1,true,1,{}\nfirst,1,nan,abc\n"),
+			},
+			want: []string{"string", "string", "string", "string"},
+		},
+		{
+			name: "test_sample_rows",
+			args: args{
+				data:       []byte("id\n1\n2\nthird\n"),
+				sampleRows: 2,
+			},
+			want: []string{"int64"},
+		},
+		{
+			name: "test_comments",
+			args: args{
+				data: []byte("id\n1\n# comment\n2\n"),
+			},
+			want: []string{"int64"},
+		},
+		{
+			name: "test_no_data",
+			args: args{
+				data: []byte(""),
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			csv := New(WithSampleRows(tt.args.sampleRows))
+
+			rslt, err := csv.InferTypes(tt.args.data)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("TestInferTypes() received error = %v", err)
+			}
+
+			if !reflect.DeepEqual(rslt, tt.want) {
+				t.Errorf("TestInferTypes() is not equal. \ngot = %+#v\nwant = %+#v", rslt, tt.want)
+			}
+		})
+	}
+}
+
+func TestCSV_InferTypes_numberFormat(t *testing.T) {
+	csv := New(WithComma(';'), WithNumberFormat(',', '.', "€"))
+
+	rslt, err := csv.InferTypes([]byte("count;price;code\n1.000;1.234,5 €;1.5\n2;3 €;2.25\n"))
+	if err != nil {
+		t.Fatalf("TestInferTypesNumberFormat() received error = %v", err)
+	}
+
+	want := []string{"int64", "float64", "string"}
+	if !reflect.DeepEqual(rslt, want) {
+		t.Errorf("TestInferTypesNumberFormat() is not equal. \ngot = %+#v\nwant = %+#v", rslt, want)
+	}
+}
+
+func TestCSV_TypedFromInferred(t *testing.T) {
+	csv := New()
+
+	rslt, err := csv.TypedFromInferred([]byte("id,price,comment,tags\n1,10.5,,\"a,b\"\n2,3,first,c\n"))
+	if err != nil {
+		t.Fatalf("TestTypedFromInferred() received error = %v", err)
+	}
+
+	first := "first"
+	want := []map[string]interface{}{
+		{"id": int64(1), "price": 10.5, "comment": nil, "tags": []string{"a", "b"}},
+		{"id": int64(2), "price": float64(3), "comment": &first, "tags": []string{"c"}},
+	}
+	if !reflect.DeepEqual(rslt, want) {
+		t.Errorf("TestTypedFromInferred() is not equal. \ngot = %+#v\nwant = %+#v", rslt, want)
+	}
+}
@@ -0,0 +1,254 @@
+package csvx
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+var (
+	ErrInvalidHeaderPath     = errors.New("invalid header path")
+	ErrConflictingHeaderPath = errors.New("conflicting header path")
+)
+
+// pathElem is a single step of a nested header path, either the key of an object or the index of an array.
+type pathElem struct {
+	key     string
+	index   int
+	isIndex bool
+}
+
+// parsePath splits a header name like "address.street" or "items[0].name" into its path elements.
+func parsePath(name string) ([]pathElem, error) {
+	path := []pathElem{}
+	for _, part := range strings.Split(name, ".") {
+		key, rest := part, ""
+		if idx := strings.IndexByte(part, '['); idx >= 0 {
+			key, rest = part[:idx], part[idx:]
+		}
+
+		if key == "" {
+			return nil, fmt.Errorf("%w: %q has an empty key", ErrInvalidHeaderPath, name)
+		}
+		path = append(path, pathElem{key: key})
+
+		for rest != "" {
+			end := strings.IndexByte(rest, ']')
+			if rest[0] != '[' || end < 0 {
+				return nil, fmt.Errorf("%w: %q has an unterminated index", ErrInvalidHeaderPath, name)
+			}
+
+			index, err := strconv.Atoi(rest[1:end])
+			if err != nil || index < 0 {
+				return nil, fmt.Errorf("%w: %q has an invalid index %q", ErrInvalidHeaderPath, name, rest[1:end])
+			}
+
+			path = append(path, pathElem{index: index, isIndex: true})
+			rest = rest[end+1:]
+		}
+	}
+
+	return path, nil
+}
+
+// pathNode is used to detect header paths that conflict with each other.
+type pathNode struct {
+	// name is the header name of the first column that uses the node.
+	name     string
+	leaf     bool
+	isArray  bool
+	children map[pathElem]*pathNode
+}
+
+// headerPaths parses the header names into paths and checks that they do not conflict with each other,
+// like "address" and "address.street", or "tags[0]" and "tags.first".
+//
+// Columns with the same name share the same path, as they are handled by the duplicate header policy.
+func (c *CSVParser) headerPaths(headerInfo map[int]field) (map[string][]pathElem, error) {
+	paths := map[string][]pathElem{}
+	root := &pathNode{children: map[pathElem]*pathNode{}}
+
+	for idx := 0; idx < len(headerInfo); idx++ {
+		name := headerInfo[idx].Name
+		if c.isTyped && headerInfo[idx].Type == "" && c.SkipEmptyColumns {
+			continue
+		}
+		if _, ok := paths[name]; ok {
+			continue
+		}
+
+		path, err := parsePath(name)
+		if err != nil {
+			return nil, fmt.Errorf("%w in column %d", err, idx+1)
+		}
+
+		if err := root.insert(name, path); err != nil {
+			return nil, err
+		}
+		paths[name] = path
+	}
+
+	if c.OverflowKey != "" {
+		// the overflow key is not split into a path, but must not conflict with the paths of the header
+		path := []pathElem{{key: c.OverflowKey}}
+		if err := root.insert(c.OverflowKey, path); err != nil {
+			return nil, err
+		}
+		paths[c.OverflowKey] = path
+	}
+
+	return paths, nil
+}
+
+// insert adds the path of the named column to the tree of paths.
+func (n *pathNode) insert(name string, path []pathElem) error {
+	for idx, elem := range path {
+		if n.leaf {
+			return fmt.Errorf("%w: %q conflicts with %q", ErrConflictingHeaderPath, name, n.name)
+		}
+		if len(n.children) > 0 && n.isArray != elem.isIndex {
+			return fmt.Errorf("%w: %q conflicts with %q", ErrConflictingHeaderPath, name, n.name)
+		}
+
+		n.isArray = elem.isIndex
+		child, ok := n.children[elem]
+		if !ok {
+			child = &pathNode{name: name, leaf: idx == len(path)-1, children: map[pathElem]*pathNode{}}
+			n.children[elem] = child
+		} else if idx == len(path)-1 {
+			// the path ends on a node used by another column, like "tags[0]" and "tags[00]"
+			return fmt.Errorf("%w: %q conflicts with %q", ErrConflictingHeaderPath, name, child.name)
+		}
+
+		n = child
+	}
+
+	return nil
+}
+
+// nestRow converts the row into a map, in which the values of columns with a header path are nested
+// into maps and slices. Array elements without a column in the row are nil.
+func nestRow(row Row, paths map[string][]pathElem) map[string]interface{} {
+	rslt := map[string]interface{}{}
+	for idx, name := range row.columns {
+		path, ok := paths[name]
+		if !ok {
+			path = []pathElem{{key: name}}
+		}
+
+		setPath(rslt, path, row.values[idx])
+	}
+
+	return rslt
+}
+
+// setPath sets the value at the path within the container, which is created if it is nil,
+// and returns the container.
+func setPath(container interface{}, path []pathElem, value interface{}) interface{} {
+	if len(path) == 0 {
+		return value
+	}
+
+	elem := path[0]
+	if !elem.isIndex {
+		m, _ := container.(map[string]interface{})
+		if m == nil {
+			m = map[string]interface{}{}
+		}
+
+		m[elem.key] = setPath(m[elem.key], path[1:], value)
+		return m
+	}
+
+	s, _ := container.([]interface{})
+	for len(s) <= elem.index {
+		s = append(s, nil)
+	}
+
+	s[elem.index] = setPath(s[elem.index], path[1:], value)
+	return s
+}
+
+// flattenMap converts a map with nested maps and slices into a row, whose column names are the header paths
+// of the leaves, sorted by path. Empty maps and slices are leaves, so they are written as json.
+func flattenMap(v reflect.Value) (Row, error) {
+	m, ok := v.Interface().(map[string]interface{})
+	if !ok {
+		m = make(map[string]interface{}, v.Len())
+		for _, key := range v.MapKeys() {
+			m[key.String()] = v.MapIndex(key).Interface()
+		}
+	}
+
+	row := Row{}
+	err := flattenKeys(&row, "", m)
+	return row, err
+}
+
+// flattenKeys adds the leaves of the map to the row, with the prefix in front of the keys.
+func flattenKeys(row *Row, prefix string, m map[string]interface{}) error {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		if key == "" || strings.ContainsAny(key, ".[]") {
+			return fmt.Errorf("%w: key %q cannot be written as part of a header path", ErrInvalidHeaderPath, key)
+		}
+
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		name := key
+		if prefix != "" {
+			name = prefix + "." + key
+		}
+
+		if err := flattenValue(row, name, m[key]); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// flattenValue adds the value to the row, or the leaves of the value if it is a non-empty map or slice.
+func flattenValue(row *Row, name string, value interface{}) error {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		if len(v) > 0 {
+			return flattenKeys(row, name, v)
+		}
+	case []interface{}:
+		if len(v) > 0 {
+			for idx, elem := range v {
+				if err := flattenValue(row, fmt.Sprintf("%s[%d]", name, idx), elem); err != nil {
+					return err
+				}
+			}
+			return nil
+		}
+	}
+
+	row.add(name, value)
+	return nil
+}
+
+// lessPath reports whether the header path a is sorted before b.
+// Keys are sorted by name and indexes by number, so "tags[2]" comes before "tags[10]".
+func lessPath(a, b []pathElem) bool {
+	for idx := 0; idx < len(a) && idx < len(b); idx++ {
+		if a[idx] == b[idx] {
+			continue
+		}
+		if a[idx].isIndex && b[idx].isIndex {
+			return a[idx].index < b[idx].index
+		}
+
+		return a[idx].key < b[idx].key
+	}
+
+	return len(a) < len(b)
+}
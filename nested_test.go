@@ -0,0 +1,269 @@
+package csvx
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestCSV_NestedHeaders(t *testing.T) {
+	type args struct {
+		data  []byte
+		typed bool
+	}
+	tests := []struct {
+		name    string
+		args    args
+		want    []map[string]interface{}
+		wantErr error
+	}{
+		{
+			name: "test_objects",
+			args: args{
+				data:  []byte("id,address.street,address.zip,address.geo.lat\nint,string,int64,float64\n1,main,12345,52.5"),
+				typed: true,
+			},
+			want: []map[string]interface{}{
+				{
+					"id": 1,
+					"address": map[string]interface{}{
+						"street": "main",
+						"zip":    int64(12345),
+						"geo":    map[string]interface{}{"lat": 52.5},
+					},
+				},
+			},
+		},
+		{
+			name: "test_arrays",
+			args: args{
+				data:  []byte("tags[0],tags[2],items[0].name,items[0].count,items[1].name\nstring,string,string,*int,string\nfirst,third,apple,,pear"),
+				typed: true,
+			},
+			want: []map[string]interface{}{
+				{
+					"tags": []interface{}{"first", nil, "third"},
+					"items": []interface{}{
+						map[string]interface{}{"name": "apple", "count": nil},
+						map[string]interface{}{"name": "pear"},
+					},
+				},
+			},
+		},
+		{
+			name: "test_nested_arrays",
+			args: args{
+				data: []byte("matrix[0][0],matrix[0][1],matrix[1][0]\n1,2,3"),
+			},
+			want: []map[string]interface{}{
+				{
+					"matrix": []interface{}{
+						[]interface{}{"1", "2"},
+						[]interface{}{"3"},
+					},
+				},
+			},
+		},
+		{
+			name: "test_short_row",
+			args: args{
+				data: []byte("a.b,a.c,d[0]\nfirst"),
+			},
+			want: []map[string]interface{}{
+				{"a": map[string]interface{}{"b": "first"}},
+			},
+		},
+		{
+			name: "test_conflict_leaf_object",
+			args: args{
+				data: []byte("address,address.street\nfirst,second"),
+			},
+			wantErr: ErrConflictingHeaderPath,
+		},
+		{
+			name: "test_conflict_object_leaf",
+			args: args{
+				data: []byte("address.street,address\nfirst,second"),
+			},
+			wantErr: ErrConflictingHeaderPath,
+		},
+		{
+			name: "test_conflict_array_object",
+			args: args{
+				data: []byte("tags[0],tags.first\nfirst,second"),
+			},
+			wantErr: ErrConflictingHeaderPath,
+		},
+		{
+			name: "test_conflict_same_index",
+			args: args{
+				data: []byte("tags[0],tags[00]\nfirst,second"),
+			},
+			wantErr: ErrConflictingHeaderPath,
+		},
+		{
+			name: "test_invalid_empty_key",
+			args: args{
+				data: []byte("address..street\nfirst"),
+			},
+			wantErr: ErrInvalidHeaderPath,
+		},
+		{
+			name: "test_invalid_index",
+			args: args{
+				data: []byte("tags[first]\nfirst"),
+			},
+			wantErr: ErrInvalidHeaderPath,
+		},
+		{
+			name: "test_invalid_unterminated_index",
+			args: args{
+				data: []byte("tags[0\nfirst"),
+			},
+			wantErr: ErrInvalidHeaderPath,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			csv := New(WithNestedHeaders(true))
+
+			var rslt []map[string]interface{}
+			var err error
+			if tt.args.typed {
+				rslt, err = csv.Typed(tt.args.data)
+			} else {
+				rslt, err = csv.Untyped(tt.args.data)
+			}
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("TestNestedHeaders() received error = %v, want %v", err, tt.wantErr)
+			}
+
+			if !reflect.DeepEqual(rslt, tt.want) {
+				t.Errorf("TestNestedHeaders() is not equal. \ngot = %+#v\nwant = %+#v", rslt, tt.want)
+			}
+		})
+	}
+}
+
+func TestCSV_NestedHeaders_disabled(t *testing.T) {
+	csv := New()
+
+	rslt, err := csv.Untyped([]byte("address.street,tags[0]\nmain,first"))
+	if err != nil {
+		t.Fatalf("TestNestedHeadersDisabled() received error = %v", err)
+	}
+
+	want := []map[string]interface{}{{"address.street": "main", "tags[0]": "first"}}
+	if !reflect.DeepEqual(rslt, want) {
+		t.Errorf("TestNestedHeadersDisabled() is not equal. \ngot = %+#v\nwant = %+#v", rslt, want)
+	}
+}
+
+func TestCSV_NestedHeaders_Unmarshal(t *testing.T) {
+	type person struct {
+		Name   string `csv:"name"`
+		Street string `csv:"address.street"`
+	}
+
+	csv := New(WithNestedHeaders(true))
+
+	var rslt []person
+	err := csv.Unmarshal([]byte("name,address.street\nstring,string\nfirst,main"), &rslt)
+	if err != nil {
+		t.Fatalf("TestNestedHeadersUnmarshal() received error = %v", err)
+	}
+
+	want := []person{{Name: "first", Street: "main"}}
+	if !reflect.DeepEqual(rslt, want) {
+		t.Errorf("TestNestedHeadersUnmarshal() is not equal. \ngot = %+#v\nwant = %+#v", rslt, want)
+	}
+}
+
+func TestCSV_Marshal_nested(t *testing.T) {
+	data := []map[string]interface{}{
+		{
+			"id": int64(1),
+			"address": map[string]interface{}{
+				"street": "main",
+				"geo":    map[string]interface{}{"lat": 52.5},
+			},
+			"tags":  []interface{}{"a", "b", "c", "d", "e", "f", "g", "h", "i", "j", "k"},
+			"names": []string{"first", "second"},
+			"meta":  map[string]interface{}{},
+		},
+		{
+			"id": int64(2),
+			"address": map[string]interface{}{
+				"street": "side",
+				"geo":    map[string]interface{}{"lat": 13.4},
+			},
+			"tags":  []interface{}{"a", "b", "c", "d", "e", "f", "g", "h", "i", "j", "k"},
+			"names": []string{},
+			"meta":  map[string]interface{}{},
+		},
+	}
+
+	csv := New(WithNestedHeaders(true))
+
+	encoded, err := csv.Marshal(data)
+	if err != nil {
+		t.Fatalf("TestMarshalNested() received error = %v", err)
+	}
+
+	want := "address.geo.lat,address.street,id,meta,names," +
+		"tags[0],tags[1],tags[2],tags[3],tags[4],tags[5],tags[6],tags[7],tags[8],tags[9],tags[10]\n" +
+		"float64,string,int64,json,\"string,array\"," +
+		"string,string,string,string,string,string,string,string,string,string,string\n"
+	if !strings.HasPrefix(string(encoded), want) {
+		t.Fatalf("TestMarshalNested() is not equal. \ngot = %q\nwant prefix = %q", encoded, want)
+	}
+
+	rslt, err := csv.Typed(encoded)
+	if err != nil {
+		t.Fatalf("TestMarshalNested() received error = %v", err)
+	}
+
+	if !reflect.DeepEqual(rslt, data) {
+		t.Errorf("TestMarshalNested() is not equal. \ngot = %+#v\nwant = %+#v", rslt, data)
+	}
+}
+
+func TestCSV_Marshal_nested_invalid(t *testing.T) {
+	tests := []struct {
+		name    string
+		value   interface{}
+		wantErr error
+	}{
+		{
+			name: "test_conflicting_rows",
+			value: []map[string]interface{}{
+				{"meta": map[string]interface{}{}},
+				{"meta": map[string]interface{}{"active": true}},
+			},
+			wantErr: ErrConflictingHeaderPath,
+		},
+		{
+			name: "test_key_with_dot",
+			value: map[string]interface{}{
+				"address": map[string]interface{}{"street.name": "main"},
+			},
+			wantErr: ErrInvalidHeaderPath,
+		},
+		{
+			name:    "test_key_with_index",
+			value:   map[string]interface{}{"tags[0]": "first"},
+			wantErr: ErrInvalidHeaderPath,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			csv := New(WithNestedHeaders(true))
+
+			_, err := csv.Marshal(tt.value)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("TestMarshalNestedInvalid() received error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
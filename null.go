@@ -0,0 +1,43 @@
+package csvx
+
+import (
+	"strings"
+)
+
+// isNull reports whether the value of a cell is one of the null tokens.
+func (c *CSVParser) isNull(value string) bool {
+	for _, token := range c.NullTokens {
+		if value == token {
+			return true
+		}
+	}
+
+	return false
+}
+
+// nullToken returns the value that is written for nil values, which is the first null token or an empty cell.
+func (c *CSVParser) nullToken() string {
+	if len(c.NullTokens) == 0 {
+		return ""
+	}
+
+	return c.NullTokens[0]
+}
+
+// convertCell converts the value of a cell into the type of the column.
+//
+// Null tokens are converted like empty cells. If null tokens are set, empty cells of *string columns
+// are converted into pointers to an empty string, so they can be told apart from null.
+func (c *CSVParser) convertCell(value, typ string) (interface{}, error) {
+	format, isPointer := strings.TrimPrefix(typ, "*"), strings.HasPrefix(typ, "*")
+
+	if c.isNull(value) {
+		return c.toTyped("", format, isPointer)
+	}
+
+	if value == "" && isPointer && format == "string" && len(c.NullTokens) > 0 {
+		return &value, nil
+	}
+
+	return c.toTyped(value, format, isPointer)
+}
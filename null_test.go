@@ -0,0 +1,122 @@
+package csvx
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestCSV_NullTokens(t *testing.T) {
+	empty := ""
+	first := "first"
+
+	type args struct {
+		data   []byte
+		typed  bool
+		tokens []string
+	}
+	tests := []struct {
+		name string
+		args args
+		want []map[string]interface{}
+	}{
+		{
+			name: "test_typed",
+			args: args{
+				data: []byte("a,b,c,d,e,f,g\n" +
+					"*string,*string,int64,*int64,json,\"string,array\",\"*string,array\"\n" +
+					"NULL,,NULL,\\N,\\N,NULL,NULL\n" +
+					"first,NULL,10,10,{},\"a,b\",a\n"),
+				typed:  true,
+				tokens: []string{"NULL", `\N`},
+			},
+			want: []map[string]interface{}{
+				{"a": nil, "b": &empty, "c": int64(0), "d": nil, "e": nil, "f": []string{}, "g": nil},
+				{"a": &first, "b": nil, "c": int64(10), "d": func(i int64) *int64 { return &i }(10), "e": map[string]interface{}{}, "f": []string{"a", "b"}, "g": &[]string{"a"}},
+			},
+		},
+		{
+			name: "test_typed_without_tokens",
+			args: args{
+				data:  []byte("a,b\n*string,*string\nNULL,\n"),
+				typed: true,
+			},
+			want: []map[string]interface{}{
+				{"a": func(s string) *string { return &s }("NULL"), "b": nil},
+			},
+		},
+		{
+			name: "test_missing",
+			args: args{
+				data:   []byte("a,b,c\n*string,*string,*int64\n,NULL\n"),
+				typed:  true,
+				tokens: []string{"NULL"},
+			},
+			want: []map[string]interface{}{
+				{"a": &empty, "b": nil},
+			},
+		},
+		{
+			name: "test_untyped",
+			args: args{
+				data:   []byte("a,b,c\nNULL,,first\n"),
+				tokens: []string{"NULL"},
+			},
+			want: []map[string]interface{}{
+				{"a": nil, "b": "", "c": "first"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			csv := New(WithNullTokens(tt.args.tokens...))
+
+			var rslt []map[string]interface{}
+			var err error
+			if tt.args.typed {
+				rslt, err = csv.Typed(tt.args.data)
+			} else {
+				rslt, err = csv.Untyped(tt.args.data)
+			}
+			if err != nil {
+				t.Fatalf("TestNullTokens() received error = %v", err)
+			}
+
+			if !reflect.DeepEqual(rslt, tt.want) {
+				t.Errorf("TestNullTokens() is not equal. \ngot = %+#v\nwant = %+#v", rslt, tt.want)
+			}
+		})
+	}
+}
+
+func TestCSV_Marshal_nullTokens(t *testing.T) {
+	type record struct {
+		Name    *string `csv:"name"`
+		Comment *string `csv:"comment"`
+		Count   *int64  `csv:"count"`
+	}
+
+	empty := ""
+	data := []record{{Name: &empty}}
+
+	csv := New(WithNullTokens("NULL"))
+
+	encoded, err := csv.Marshal(data)
+	if err != nil {
+		t.Fatalf("TestMarshalNullTokens() received error = %v", err)
+	}
+
+	want := "name,comment,count\n*string,*string,*int64\n,NULL,NULL\n"
+	if string(encoded) != want {
+		t.Errorf("TestMarshalNullTokens() is not equal. \ngot = %q\nwant = %q", encoded, want)
+	}
+
+	var rslt []record
+	err = csv.Unmarshal(encoded, &rslt)
+	if err != nil {
+		t.Fatalf("TestMarshalNullTokens() received error = %v", err)
+	}
+
+	if !reflect.DeepEqual(rslt, data) {
+		t.Errorf("TestMarshalNullTokens() is not equal. \ngot = %+#v\nwant = %+#v", rslt, data)
+	}
+}
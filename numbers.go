@@ -0,0 +1,315 @@
+package csvx
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"reflect"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+var ErrInvalidNumberFormat = errors.New("invalid number format")
+
+// numberTypes contains the integer and float types that are supported in addition to int64 and float64.
+// int itself is converted by toTyped, it is listed for its array variant.
+var numberTypes = map[string]reflect.Type{
+	"int":     reflect.TypeOf(int(0)),
+	"int8":    reflect.TypeOf(int8(0)),
+	"int16":   reflect.TypeOf(int16(0)),
+	"int32":   reflect.TypeOf(int32(0)),
+	"uint":    reflect.TypeOf(uint(0)),
+	"uint8":   reflect.TypeOf(uint8(0)),
+	"uint16":  reflect.TypeOf(uint16(0)),
+	"uint32":  reflect.TypeOf(uint32(0)),
+	"uint64":  reflect.TypeOf(uint64(0)),
+	"float32": reflect.TypeOf(float32(0)),
+}
+
+// numberType returns the conversion function and the go type for the additional number types.
+// Values that do not fit into the type are rejected with strconv.ErrRange.
+func numberType(name string) (TypeFunc, reflect.Type, bool) {
+	goType, ok := numberTypes[name]
+	if !ok {
+		return nil, nil, false
+	}
+
+	return func(value string) (interface{}, error) {
+		rv := reflect.New(goType).Elem()
+		if value == "" {
+			return rv.Interface(), nil
+		}
+
+		switch goType.Kind() {
+		case reflect.Float32:
+			val, err := strconv.ParseFloat(value, goType.Bits())
+			if err != nil {
+				return nil, err
+			}
+			rv.SetFloat(val)
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+			val, err := strconv.ParseUint(value, 10, goType.Bits())
+			if err != nil {
+				return nil, err
+			}
+			rv.SetUint(val)
+		default:
+			val, err := strconv.ParseInt(value, 10, goType.Bits())
+			if err != nil {
+				return nil, err
+			}
+			rv.SetInt(val)
+		}
+
+		return rv.Interface(), nil
+	}, goType, true
+}
+
+// bigType returns the conversion function and the go type for the arbitrary-precision types
+// bigint (*big.Int), decimal (*big.Rat) and bigfloat (*big.Float).
+// Empty values are converted into zero.
+func bigType(name string) (TypeFunc, reflect.Type, bool) {
+	switch name {
+	case "bigint":
+		return func(value string) (interface{}, error) {
+			val := new(big.Int)
+			if value == "" {
+				return val, nil
+			}
+
+			if _, ok := val.SetString(value, 10); !ok {
+				return nil, &strconv.NumError{Func: "SetString", Num: value, Err: strconv.ErrSyntax}
+			}
+
+			return val, nil
+		}, reflect.TypeOf(&big.Int{}), true
+	case "decimal":
+		return func(value string) (interface{}, error) {
+			val := new(big.Rat)
+			if value == "" {
+				return val, nil
+			}
+
+			if _, ok := val.SetString(value); !ok {
+				return nil, &strconv.NumError{Func: "SetString", Num: value, Err: strconv.ErrSyntax}
+			}
+
+			return val, nil
+		}, reflect.TypeOf(&big.Rat{}), true
+	case "bigfloat":
+		return func(value string) (interface{}, error) {
+			if value == "" {
+				return new(big.Float), nil
+			}
+
+			// every decimal digit needs less than 4 bits, so the precision is sufficient to keep all digits
+			prec := uint(len(value)) * 4
+			if prec < 64 {
+				prec = 64
+			}
+
+			val, _, err := big.ParseFloat(value, 10, prec, big.ToNearestEven)
+			if err != nil {
+				return nil, &strconv.NumError{Func: "ParseFloat", Num: value, Err: strconv.ErrSyntax}
+			}
+
+			return val, nil
+		}, reflect.TypeOf(&big.Float{}), true
+	default:
+		return nil, nil, false
+	}
+}
+
+// formatBig converts a *big.Int, *big.Rat or *big.Float into its csv representation.
+//
+// Decimals are written with as many decimal places as needed to be exact. Fractions without
+// finite decimal representation, like 1/3, are written as fraction.
+func formatBig(value interface{}) (string, bool) {
+	switch v := value.(type) {
+	case *big.Int:
+		if v == nil {
+			return "", true
+		}
+
+		return v.String(), true
+	case *big.Rat:
+		if v == nil {
+			return "", true
+		}
+
+		places, ok := decimalPlaces(v.Denom())
+		if !ok {
+			return v.RatString(), true
+		}
+
+		return v.FloatString(places), true
+	case *big.Float:
+		if v == nil {
+			return "", true
+		}
+
+		return v.Text('g', -1), true
+	default:
+		return "", false
+	}
+}
+
+// decimalPlaces returns the number of decimal places needed for the exact representation of a
+// fraction with the given denominator. This is only possible if its only prime factors are 2 and 5.
+func decimalPlaces(denom *big.Int) (int, bool) {
+	rest := new(big.Int).Set(denom)
+	mod := new(big.Int)
+
+	counts := [2]int{}
+	for idx, factor := range []*big.Int{big.NewInt(2), big.NewInt(5)} {
+		for {
+			quo, _ := new(big.Int).QuoRem(rest, factor, mod)
+			if mod.Sign() != 0 {
+				break
+			}
+
+			rest = quo
+			counts[idx]++
+		}
+	}
+
+	if rest.Cmp(big.NewInt(1)) != 0 {
+		return 0, false
+	}
+
+	if counts[0] > counts[1] {
+		return counts[0], true
+	}
+
+	return counts[1], true
+}
+
+// hasNumberFormat reports whether a number format differing from strconv is configured.
+func (c *CSVParser) hasNumberFormat() bool {
+	return (c.DecimalSeparator != 0 && c.DecimalSeparator != '.') || c.GroupSeparator != 0 || len(c.NumberSuffixes) > 0
+}
+
+// normalizeNumber converts a number in the configured number format, like "1.234,56 €",
+// into the format of strconv, like "1234.56".
+//
+// Grouping separators must separate groups of three digits in front of the decimal separator.
+// ErrInvalidNumberFormat is returned, if the decimal and the grouping separator are the same.
+func (c *CSVParser) normalizeNumber(value string) (string, error) {
+	if !c.hasNumberFormat() {
+		return value, nil
+	}
+
+	num := value
+	for _, suffix := range c.NumberSuffixes {
+		if suffix != "" && strings.HasSuffix(num, suffix) {
+			num = strings.TrimSpace(strings.TrimSuffix(num, suffix))
+			break
+		}
+	}
+
+	decimal := c.DecimalSeparator
+	if decimal == 0 {
+		decimal = '.'
+	}
+	if c.GroupSeparator == decimal {
+		return "", fmt.Errorf("%w: decimal and grouping separator are both %q", ErrInvalidNumberFormat, decimal)
+	}
+	if decimal != '.' && c.GroupSeparator != '.' && strings.ContainsRune(num, '.') {
+		// a point is neither decimal nor grouping separator
+		return "", &strconv.NumError{Func: "ParseFloat", Num: value, Err: strconv.ErrSyntax}
+	}
+
+	intPart, fracPart, hasFrac := num, "", false
+	if idx := strings.IndexRune(num, decimal); idx >= 0 {
+		intPart, fracPart, hasFrac = num[:idx], num[idx+utf8.RuneLen(decimal):], true
+	}
+
+	if c.GroupSeparator != 0 && strings.ContainsRune(intPart, c.GroupSeparator) {
+		groups := strings.Split(intPart, string(c.GroupSeparator))
+		for idx, group := range groups {
+			digits := len(group)
+			if idx == 0 {
+				digits = len(strings.TrimLeft(group, "+-"))
+			}
+
+			if (idx == 0 && (digits == 0 || digits > 3)) || (idx > 0 && digits != 3) {
+				return "", &strconv.NumError{Func: "ParseFloat", Num: value, Err: strconv.ErrSyntax}
+			}
+		}
+
+		intPart = strings.Join(groups, "")
+	}
+
+	if !hasFrac {
+		return intPart, nil
+	}
+
+	return intPart + "." + fracPart, nil
+}
+
+// withNumberFormat returns a conversion function, which normalizes the values with the configured
+// number format before they are converted with fn.
+func (c *CSVParser) withNumberFormat(fn TypeFunc) TypeFunc {
+	if !c.hasNumberFormat() {
+		return fn
+	}
+
+	return func(value string) (interface{}, error) {
+		if value == "" {
+			return fn(value)
+		}
+
+		num, err := c.normalizeNumber(value)
+		if err != nil {
+			return nil, err
+		}
+
+		return fn(num)
+	}
+}
+
+// formatNumber is the inverse of normalizeNumber and writes a number formatted by strconv,
+// like "1234.56", with the configured separators, like "1.234,56". Suffixes are not written.
+func (c *CSVParser) formatNumber(num string) string {
+	if !c.hasNumberFormat() {
+		return num
+	}
+
+	mantissa, exponent := num, ""
+	if idx := strings.IndexAny(num, "eE"); idx >= 0 {
+		mantissa, exponent = num[:idx], num[idx:]
+	}
+
+	intPart, fracPart := mantissa, ""
+	if idx := strings.IndexByte(mantissa, '.'); idx >= 0 {
+		intPart, fracPart = mantissa[:idx], mantissa[idx+1:]
+	}
+
+	sign := ""
+	if strings.HasPrefix(intPart, "-") {
+		sign, intPart = "-", intPart[1:]
+	}
+
+	if c.GroupSeparator != 0 && strings.Trim(intPart, "0123456789") == "" {
+		var b strings.Builder
+		for idx, r := range intPart {
+			if idx > 0 && (len(intPart)-idx)%3 == 0 {
+				b.WriteRune(c.GroupSeparator)
+			}
+			b.WriteRune(r)
+		}
+		intPart = b.String()
+	}
+
+	if fracPart != "" || strings.HasSuffix(mantissa, ".") {
+		decimal := c.DecimalSeparator
+		if decimal == 0 {
+			decimal = '.'
+		}
+
+		return sign + intPart + string(decimal) + fracPart + exponent
+	}
+
+	return sign + intPart + exponent
+}
@@ -0,0 +1,496 @@
+package csvx
+
+import (
+	"errors"
+	"math/big"
+	"reflect"
+	"strconv"
+	"testing"
+)
+
+func TestCSV_toTyped_numbers(t *testing.T) {
+	type args struct {
+		value, format string
+		isPointerType bool
+	}
+	tests := []struct {
+		name    string
+		args    args
+		want    interface{}
+		wantErr error
+	}{
+		{
+			name: "test_int8",
+			args: args{value: "-128", format: "int8"},
+			want: int8(-128),
+		},
+		{
+			name:    "test_int8_overflow",
+			args:    args{value: "128", format: "int8"},
+			wantErr: strconv.ErrRange,
+		},
+		{
+			name: "test_int16",
+			args: args{value: "32767", format: "int16"},
+			want: int16(32767),
+		},
+		{
+			name: "test_int32",
+			args: args{value: "-2147483648", format: "int32"},
+			want: int32(-2147483648),
+		},
+		{
+			name:    "test_int32_overflow",
+			args:    args{value: "2147483648", format: "int32"},
+			wantErr: strconv.ErrRange,
+		},
+		{
+			name: "test_uint",
+			args: args{value: "10", format: "uint"},
+			want: uint(10),
+		},
+		{
+			name: "test_uint8",
+			args: args{value: "255", format: "uint8"},
+			want: uint8(255),
+		},
+		{
+			name:    "test_uint8_overflow",
+			args:    args{value: "256", format: "uint8"},
+			wantErr: strconv.ErrRange,
+		},
+		{
+			name:    "test_uint16_negative",
+			args:    args{value: "-1", format: "uint16"},
+			wantErr: strconv.ErrSyntax,
+		},
+		{
+			name: "test_uint32",
+			args: args{value: "4294967295", format: "uint32"},
+			want: uint32(4294967295),
+		},
+		{
+			name: "test_uint64",
+			args: args{value: "18446744073709551615", format: "uint64"},
+			want: uint64(18446744073709551615),
+		},
+		{
+			name: "test_float32",
+			args: args{value: "10.1", format: "float32"},
+			want: float32(10.1),
+		},
+		{
+			name:    "test_float32_overflow",
+			args:    args{value: "1e39", format: "float32"},
+			wantErr: strconv.ErrRange,
+		},
+		{
+			name: "test_int16_empty",
+			args: args{value: "", format: "int16"},
+			want: int16(0),
+		},
+		{
+			name: "test_uint32_array",
+			args: args{value: "1, 2,", format: "uint32,array"},
+			want: []uint32{1, 2, 0},
+		},
+		{
+			name:    "test_int8_array_overflow",
+			args:    args{value: "1,1000", format: "int8,array"},
+			wantErr: strconv.ErrRange,
+		},
+		{
+			name: "test_float32_array",
+			args: args{value: "1.5,2.5", format: "float32,array"},
+			want: []float32{1.5, 2.5},
+		},
+
+		// pointer
+
+		{
+			name: "test_uint16_ptr",
+			args: args{value: "10", format: "uint16", isPointerType: true},
+			want: func(i uint16) *uint16 { return &i }(10),
+		},
+		{
+			name: "test_uint16_ptr_empty",
+			args: args{value: "", format: "uint16", isPointerType: true},
+			want: nil,
+		},
+		{
+			name: "test_int32_array_ptr",
+			args: args{value: "1,2", format: "int32,array", isPointerType: true},
+			want: &[]int32{1, 2},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			csv := CSVParser{Comma: ',', Comment: '#', TrimLeadingSpace: true}
+
+			rslt, err := csv.toTyped(tt.args.value, tt.args.format, tt.args.isPointerType)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("TestToTypedNumbers() received error = %v, want %v", err, tt.wantErr)
+			}
+
+			if !reflect.DeepEqual(rslt, tt.want) {
+				t.Errorf("TestToTypedNumbers() is not equal. \ngot = %+#v\nwant = %+#v", rslt, tt.want)
+			}
+		})
+	}
+}
+
+func TestCSV_Marshal_numbers(t *testing.T) {
+	type measurement struct {
+		ID     uint32    `csv:"id"`
+		Code   int16     `csv:"code"`
+		Value  float32   `csv:"value"`
+		Counts []uint64  `csv:"counts"`
+		Offset *int8     `csv:"offset"`
+		Values []float32 `csv:"values"`
+	}
+
+	data := []measurement{
+		{ID: 4294967295, Code: -5, Value: 10.1, Counts: []uint64{1, 18446744073709551615}, Values: []float32{0.1}},
+	}
+
+	csv := CSVParser{}
+
+	encoded, err := csv.Marshal(data)
+	if err != nil {
+		t.Fatalf("TestMarshalNumbers() received error = %v", err)
+	}
+
+	want := "id,code,value,counts,offset,values\n" +
+		"uint32,int16,float32,\"uint64,array\",*int8,\"float32,array\"\n" +
+		"4294967295,-5,10.1,\"1,18446744073709551615\",,0.1\n"
+	if string(encoded) != want {
+		t.Errorf("TestMarshalNumbers() is not equal. \ngot = %q\nwant = %q", encoded, want)
+	}
+
+	var rslt []measurement
+	err = csv.Unmarshal(encoded, &rslt)
+	if err != nil {
+		t.Fatalf("TestMarshalNumbers() received error = %v", err)
+	}
+
+	if !reflect.DeepEqual(rslt, data) {
+		t.Errorf("TestMarshalNumbers() is not equal. \ngot = %+#v\nwant = %+#v", rslt, data)
+	}
+}
+
+func TestCSV_toTyped_big(t *testing.T) {
+	bigInt := func(value string) *big.Int {
+		i, _ := new(big.Int).SetString(value, 10)
+		return i
+	}
+	bigRat := func(value string) *big.Rat {
+		r, _ := new(big.Rat).SetString(value)
+		return r
+	}
+
+	type args struct {
+		value, format string
+		isPointerType bool
+	}
+	tests := []struct {
+		name    string
+		args    args
+		want    interface{}
+		wantErr error
+	}{
+		{
+			name: "test_bigint",
+			args: args{value: "123456789012345678901234567890", format: "bigint"},
+			want: bigInt("123456789012345678901234567890"),
+		},
+		{
+			name: "test_bigint_empty",
+			args: args{value: "", format: "bigint"},
+			want: new(big.Int),
+		},
+		{
+			name: "test_bigint_ptr",
+			args: args{value: "-10", format: "bigint", isPointerType: true},
+			want: big.NewInt(-10),
+		},
+		{
+			name: "test_bigint_ptr_empty",
+			args: args{value: "", format: "bigint", isPointerType: true},
+			want: nil,
+		},
+		{
+			name:    "test_bigint_invalid",
+			args:    args{value: "1.5", format: "bigint"},
+			wantErr: strconv.ErrSyntax,
+		},
+		{
+			name: "test_bigint_array",
+			args: args{value: "1, 98765432109876543210", format: "bigint,array"},
+			want: []*big.Int{big.NewInt(1), bigInt("98765432109876543210")},
+		},
+		{
+			name: "test_decimal",
+			args: args{value: "1234567890.0123456789", format: "decimal"},
+			want: bigRat("1234567890.0123456789"),
+		},
+		{
+			name:    "test_decimal_invalid",
+			args:    args{value: "1,5", format: "decimal"},
+			wantErr: strconv.ErrSyntax,
+		},
+		{
+			name: "test_decimal_array_ptr",
+			args: args{value: "0.1,0.2", format: "decimal,array", isPointerType: true},
+			want: &[]*big.Rat{bigRat("0.1"), bigRat("0.2")},
+		},
+		{
+			name:    "test_bigfloat_invalid",
+			args:    args{value: "abc", format: "bigfloat"},
+			wantErr: strconv.ErrSyntax,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			csv := CSVParser{Comma: ',', Comment: '#', TrimLeadingSpace: true}
+
+			rslt, err := csv.toTyped(tt.args.value, tt.args.format, tt.args.isPointerType)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("TestToTypedBig() received error = %v, want %v", err, tt.wantErr)
+			}
+
+			if !reflect.DeepEqual(rslt, tt.want) {
+				t.Errorf("TestToTypedBig() is not equal. \ngot = %+#v\nwant = %+#v", rslt, tt.want)
+			}
+		})
+	}
+}
+
+func TestCSV_Marshal_big(t *testing.T) {
+	type price struct {
+		ID     *big.Int   `csv:"id"`
+		Amount *big.Rat   `csv:"amount"`
+		Ratio  *big.Rat   `csv:"ratio"`
+		Exact  *big.Float `csv:"exact"`
+	}
+
+	data := "id,amount,ratio,exact\n" +
+		"*bigint,*decimal,*decimal,*bigfloat\n" +
+		"123456789012345678901234567890,1234567890.0123456789,1/3,3.14159265358979323846264338327950288\n" +
+		",0.5,-12,\n"
+
+	csv := CSVParser{}
+
+	var prices []price
+	err := csv.Unmarshal([]byte(data), &prices)
+	if err != nil {
+		t.Fatalf("TestMarshalBig() received error = %v", err)
+	}
+
+	encoded, err := csv.Marshal(prices)
+	if err != nil {
+		t.Fatalf("TestMarshalBig() received error = %v", err)
+	}
+
+	if string(encoded) != data {
+		t.Errorf("TestMarshalBig() is not equal. \ngot = %q\nwant = %q", encoded, data)
+	}
+}
+
+func TestDecimalPlaces(t *testing.T) {
+	tests := []struct {
+		denom  int64
+		want   int
+		wantOk bool
+	}{
+		{denom: 1, want: 0, wantOk: true},
+		{denom: 2, want: 1, wantOk: true},
+		{denom: 8, want: 3, wantOk: true},
+		{denom: 40, want: 3, wantOk: true},
+		{denom: 625, want: 4, wantOk: true},
+		{denom: 3, wantOk: false},
+		{denom: 30, wantOk: false},
+	}
+	for _, tt := range tests {
+		places, ok := decimalPlaces(big.NewInt(tt.denom))
+		if places != tt.want || ok != tt.wantOk {
+			t.Errorf("TestDecimalPlaces(%d) = %d, %v, want %d, %v", tt.denom, places, ok, tt.want, tt.wantOk)
+		}
+	}
+}
+
+func TestCSV_toTyped_numberFormat(t *testing.T) {
+	type args struct {
+		value, format string
+		isPointerType bool
+	}
+	tests := []struct {
+		name    string
+		args    args
+		want    interface{}
+		wantErr error
+	}{
+		{
+			name: "test_float64",
+			args: args{value: "1.234,56", format: "float64"},
+			want: 1234.56,
+		},
+		{
+			name: "test_float64_suffix",
+			args: args{value: "1.234,5 €", format: "float64"},
+			want: 1234.5,
+		},
+		{
+			name: "test_float64_negative",
+			args: args{value: "-1.234.567,5", format: "float64"},
+			want: -1234567.5,
+		},
+		{
+			name: "test_int64_ungrouped",
+			args: args{value: "1234", format: "int64"},
+			want: int64(1234),
+		},
+		{
+			name: "test_int_percent",
+			args: args{value: "15%", format: "*int", isPointerType: true},
+			want: func() *int { v := 15; return &v }(),
+		},
+		{
+			name: "test_int8",
+			args: args{value: "-12 %", format: "int8"},
+			want: int8(-12),
+		},
+		{
+			name: "test_float64_array",
+			args: args{value: "1.000,5;2,25 €", format: "float64,array"},
+			want: []float64{1000.5, 2.25},
+		},
+		{
+			name: "test_int64_array",
+			args: args{value: "1.000;2", format: "int64,array"},
+			want: []int64{1000, 2},
+		},
+		{
+			name: "test_int_array",
+			args: args{value: "1.000;2", format: "int,array"},
+			want: []int{1000, 2},
+		},
+		{
+			name: "test_decimal",
+			args: args{value: "1.234,125", format: "decimal"},
+			want: big.NewRat(1234125, 1000),
+		},
+		{
+			name:    "test_invalid_group",
+			args:    args{value: "1.23,5", format: "float64"},
+			wantErr: strconv.ErrSyntax,
+		},
+		{
+			name:    "test_invalid_first_group",
+			args:    args{value: "1234.567", format: "int64"},
+			wantErr: strconv.ErrSyntax,
+		},
+		{
+			name:    "test_invalid_group_int",
+			args:    args{value: "1.5", format: "int"},
+			wantErr: strconv.ErrSyntax,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			csv := New(WithComma(';'), WithNumberFormat(',', '.', "€", "%"))
+
+			rslt, err := csv.convertCell(tt.args.value, tt.args.format)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("TestToTypedNumberFormat() received error = %v, want %v", err, tt.wantErr)
+			}
+
+			if !reflect.DeepEqual(rslt, tt.want) {
+				t.Errorf("TestToTypedNumberFormat() is not equal. \ngot = %+#v\nwant = %+#v", rslt, tt.want)
+			}
+		})
+	}
+}
+
+func TestCSV_normalizeNumber(t *testing.T) {
+	tests := []struct {
+		value, want string
+		decimal     rune
+		group       rune
+		wantErr     bool
+	}{
+		{value: "1,234.5", want: "1234.5", decimal: '.', group: ','},
+		{value: "1 234 567", want: "1234567", decimal: ',', group: ' '},
+		{value: "1'234,5", want: "1234.5", decimal: ',', group: '\''},
+		{value: "1,5e3", want: "1.5e3", decimal: ','},
+		{value: "+1.000", want: "+1000", decimal: ',', group: '.'},
+		{value: "1.5", decimal: ',', wantErr: true},
+		{value: ",000", decimal: '.', group: ',', wantErr: true},
+		{value: "1,0000", decimal: '.', group: ',', wantErr: true},
+		{value: "1,5", decimal: ',', group: ',', wantErr: true},
+		{value: "1.5", group: '.', wantErr: true},
+	}
+	for _, tt := range tests {
+		csv := CSVParser{DecimalSeparator: tt.decimal, GroupSeparator: tt.group}
+
+		num, err := csv.normalizeNumber(tt.value)
+		if (err != nil) != tt.wantErr || num != tt.want {
+			t.Errorf("TestNormalizeNumber(%q) = %q, %v, want %q, error %v", tt.value, num, err, tt.want, tt.wantErr)
+		}
+	}
+}
+
+func TestCSV_Typed_sameSeparators(t *testing.T) {
+	csv := New(WithComma(';'), WithNumberFormat(',', ','))
+
+	_, err := csv.Typed([]byte("price;name\nfloat64;string\n1,5;first\n"))
+	if !errors.Is(err, ErrInvalidNumberFormat) {
+		t.Errorf("TestTypedSameSeparators() received error = %v, want %v", err, ErrInvalidNumberFormat)
+	}
+}
+
+func TestCSV_Marshal_numberFormat(t *testing.T) {
+	type price struct {
+		Net      float64    `csv:"net"`
+		Quantity int        `csv:"quantity"`
+		Total    *big.Rat   `csv:"total"`
+		Parts    []float64  `csv:"parts"`
+		Big      float64    `csv:"big"`
+		Counts   []uint32   `csv:"counts"`
+		Exact    *big.Float `csv:"exact"`
+		Large    float64    `csv:"large"`
+		Small    float32    `csv:"small"`
+	}
+
+	data := []price{
+		{Net: -1234.5, Quantity: 1000000, Total: big.NewRat(24691, 2), Parts: []float64{0.5, 1000}, Big: 1e21, Counts: []uint32{999, 1000}, Exact: big.NewFloat(2.5), Large: 1234567.5, Small: 0.00001},
+	}
+
+	csv := New(WithComma(';'), WithNumberFormat(',', '.'))
+
+	encoded, err := csv.Marshal(data)
+	if err != nil {
+		t.Fatalf("TestMarshalNumberFormat() received error = %v", err)
+	}
+
+	want := "net;quantity;total;parts;big;counts;exact;large;small\n" +
+		"float64;int;*decimal;float64,array;float64;uint32,array;*bigfloat;float64;float32\n" +
+		"-1.234,5;1.000.000;12.345,5;\"0,5;1.000\";1.000.000.000.000.000.000.000;\"999;1.000\";2,5;1.234.567,5;0,00001\n"
+	if string(encoded) != want {
+		t.Errorf("TestMarshalNumberFormat() is not equal. \ngot = %q\nwant = %q", encoded, want)
+	}
+
+	var rslt []price
+	err = csv.Unmarshal(encoded, &rslt)
+	if err != nil {
+		t.Fatalf("TestMarshalNumberFormat() received error = %v", err)
+	}
+
+	reencoded, err := csv.Marshal(rslt)
+	if err != nil {
+		t.Fatalf("TestMarshalNumberFormat() received error = %v", err)
+	}
+
+	if string(reencoded) != want {
+		t.Errorf("TestMarshalNumberFormat() is not equal. \ngot = %q\nwant = %q", reencoded, want)
+	}
+}
@@ -0,0 +1,168 @@
+package csvx
+
+import (
+	"time"
+)
+
+// Option configures a parser created with New.
+type Option func(*CSVParser)
+
+// New returns a parser with the default settings, which are changed by the given options.
+//
+// The returned parser is safe for concurrent use, as long as its fields are not changed afterwards.
+func New(opts ...Option) *CSVParser {
+	c := &CSVParser{}
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	c.checkForNilOrDefault()
+	return c
+}
+
+// WithComma sets the rune with which the entries in the csv file are separated from each other.
+func WithComma(comma rune) Option {
+	return func(c *CSVParser) {
+		c.Comma = comma
+	}
+}
+
+// WithComment sets the rune used to mark comment strings within the CSV.
+func WithComment(comment rune) Option {
+	return func(c *CSVParser) {
+		c.Comment = comment
+	}
+}
+
+// WithTrimLeadingSpace specifies whether leading spaces should be trimmed or not.
+func WithTrimLeadingSpace(trim bool) Option {
+	return func(c *CSVParser) {
+		c.TrimLeadingSpace = trim
+	}
+}
+
+// WithSkipEmptyColumns defines whether empty rows should be ignored or not.
+func WithSkipEmptyColumns(skip bool) Option {
+	return func(c *CSVParser) {
+		c.SkipEmptyColumns = skip
+	}
+}
+
+// WithLocation sets the time zone of time values without offset.
+func WithLocation(loc *time.Location) Option {
+	return func(c *CSVParser) {
+		c.Location = loc
+	}
+}
+
+// WithType registers a custom type name for the type row, which is only available for this parser.
+//
+// See RegisterType for details.
+func WithType(name string, fn TypeFunc) Option {
+	return func(c *CSVParser) {
+		c.RegisterType(name, fn)
+	}
+}
+
+// WithDuplicateHeaders defines how columns with the same header name are handled.
+func WithDuplicateHeaders(policy DuplicateHeaderPolicy) Option {
+	return func(c *CSVParser) {
+		c.DuplicateHeaders = policy
+	}
+}
+
+// WithRejectEmptyHeaders defines whether blank header names are rejected with ErrEmptyHeader.
+func WithRejectEmptyHeaders(reject bool) Option {
+	return func(c *CSVParser) {
+		c.RejectEmptyHeaders = reject
+	}
+}
+
+// WithStrictRecordWidth defines whether rows with more or fewer cells than the header are rejected with ErrRecordWidth.
+func WithStrictRecordWidth(strict bool) Option {
+	return func(c *CSVParser) {
+		c.StrictRecordWidth = strict
+	}
+}
+
+// WithOverflowKey sets the name under which the surplus cells of a row are collected.
+func WithOverflowKey(key string) Option {
+	return func(c *CSVParser) {
+		c.OverflowKey = key
+	}
+}
+
+// WithNestedHeaders defines whether header names like "address.street" or "tags[0]" build nested maps and slices.
+func WithNestedHeaders(nested bool) Option {
+	return func(c *CSVParser) {
+		c.NestedHeaders = nested
+	}
+}
+
+// WithSampleRows sets how many data rows InferTypes examines.
+func WithSampleRows(rows int) Option {
+	return func(c *CSVParser) {
+		c.SampleRows = rows
+	}
+}
+
+// WithNullTokens sets the cell values that are read as null, like "NULL" or "\N".
+func WithNullTokens(tokens ...string) Option {
+	return func(c *CSVParser) {
+		c.NullTokens = tokens
+	}
+}
+
+// WithConstraintRow defines whether the row after the header names, or after the type row for typed csv,
+// declares the constraints of the columns.
+func WithConstraintRow(constraintRow bool) Option {
+	return func(c *CSVParser) {
+		c.ConstraintRow = constraintRow
+	}
+}
+
+// WithConstraints sets the constraints of the columns by header name.
+func WithConstraints(constraints map[string]Constraint) Option {
+	return func(c *CSVParser) {
+		c.Constraints = constraints
+	}
+}
+
+// WithArraySeparator sets the rune with which the elements of array types are separated from each other.
+func WithArraySeparator(sep rune) Option {
+	return func(c *CSVParser) {
+		c.ArraySeparator = sep
+	}
+}
+
+// WithBoolValues sets the values of bool cells, like "yes" and "no" or "x" and "".
+func WithBoolValues(trueValues, falseValues []string) Option {
+	return func(c *CSVParser) {
+		c.TrueValues = trueValues
+		c.FalseValues = falseValues
+	}
+}
+
+// WithNumberFormat sets the decimal and grouping separator of number cells and the units that may follow them,
+// like WithNumberFormat(',', '.', "€") for "1.234,56 €".
+func WithNumberFormat(decimal, group rune, suffixes ...string) Option {
+	return func(c *CSVParser) {
+		c.DecimalSeparator = decimal
+		c.GroupSeparator = group
+		c.NumberSuffixes = suffixes
+	}
+}
+
+// WithEncoding sets the character encoding of the data that is read, like EncodingWindows1252.
+func WithEncoding(encoding Encoding) Option {
+	return func(c *CSVParser) {
+		c.Encoding = encoding
+	}
+}
+
+// WithSniffDialect sets whether the delimiter and comment character are detected automatically when reading.
+func WithSniffDialect(sniff bool) Option {
+	return func(c *CSVParser) {
+		c.SniffDialect = sniff
+	}
+}
@@ -0,0 +1,93 @@
+package csvx
+
+import (
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestNew(t *testing.T) {
+	tests := []struct {
+		name string
+		opts []Option
+		want *CSVParser
+	}{
+		{
+			name: "test_defaults",
+			want: &CSVParser{
+				Comma:   ',',
+				Comment: '#',
+			},
+		},
+		{
+			name: "test_options",
+			opts: []Option{
+				WithComma(';'),
+				WithComment('%'),
+				WithTrimLeadingSpace(true),
+				WithSkipEmptyColumns(true),
+				WithLocation(time.Local),
+			},
+			want: &CSVParser{
+				Comma:            ';',
+				Comment:          '%',
+				TrimLeadingSpace: true,
+				SkipEmptyColumns: true,
+				Location:         time.Local,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rslt := New(tt.opts...)
+
+			if !reflect.DeepEqual(rslt, tt.want) {
+				t.Errorf("TestNew() is not equal. \ngot = %+#v\nwant = %+#v", rslt, tt.want)
+			}
+		})
+	}
+}
+
+func TestNew_WithType(t *testing.T) {
+	csv := New(WithType("sku", parseTestSKU))
+
+	rslt, err := csv.Typed([]byte("foo\nsku\nSKU-1"))
+	if err != nil {
+		t.Fatalf("TestNewWithType() received error = %v", err)
+	}
+
+	want := []map[string]interface{}{{"foo": testSKU("SKU-1")}}
+	if !reflect.DeepEqual(rslt, want) {
+		t.Errorf("TestNewWithType() is not equal. \ngot = %+#v\nwant = %+#v", rslt, want)
+	}
+}
+
+// TestCSV_concurrent shares one parser between goroutines, run with -race to detect data races.
+func TestCSV_concurrent(t *testing.T) {
+	for _, csv := range []*CSVParser{New(WithComma(';')), {Comma: ';'}, {}} {
+		data := []byte("foo;bar\nstring;int\nfirst;10")
+		if csv.Comma == 0 {
+			data = []byte("foo,bar\nstring,int\nfirst,10")
+		}
+
+		var wg sync.WaitGroup
+		for i := 0; i < 8; i++ {
+			wg.Add(1)
+			go func(typed bool) {
+				defer wg.Done()
+
+				var err error
+				if typed {
+					_, err = csv.Typed(data)
+				} else {
+					_, err = csv.Untyped(data)
+				}
+				if err != nil {
+					t.Errorf("TestConcurrent() received error = %v", err)
+				}
+			}(i%2 == 0)
+		}
+		wg.Wait()
+	}
+}
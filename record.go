@@ -0,0 +1,28 @@
+package csvx
+
+import (
+	"errors"
+	"fmt"
+)
+
+var ErrRecordWidth = errors.New("wrong number of cells")
+
+// checkRecordWidth checks whether the row has as many cells as the header, if StrictRecordWidth is set.
+//
+// The returned *ParseError points to the first missing or surplus cell of the row.
+func (c *CSVParser) checkRecordWidth(headerInfo map[int]field, value []string, line int) *ParseError {
+	if !c.StrictRecordWidth || len(value) == len(headerInfo) {
+		return nil
+	}
+
+	column := len(value)
+	if len(headerInfo) < column {
+		column = len(headerInfo)
+	}
+
+	return &ParseError{
+		Line:   line,
+		Column: column + 1,
+		Err:    fmt.Errorf("%w: row has %d cells, header has %d", ErrRecordWidth, len(value), len(headerInfo)),
+	}
+}
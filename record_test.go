@@ -0,0 +1,138 @@
+package csvx
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestCSV_recordWidth(t *testing.T) {
+	type args struct {
+		data        []byte
+		typed       bool
+		strict      bool
+		overflowKey string
+	}
+	tests := []struct {
+		name    string
+		args    args
+		want    []map[string]interface{}
+		wantErr error
+	}{
+		{
+			name: "test_drop_surplus",
+			args: args{
+				data: []byte("foo,bar\nfirst,second,third\n,,fourth\nfifth"),
+			},
+			want: []map[string]interface{}{
+				{"foo": "first", "bar": "second"},
+				{"foo": "fifth"},
+			},
+		},
+		{
+			name: "test_drop_surplus_typed",
+			args: args{
+				data:  []byte("foo,bar\nstring,int\nfirst,10,third"),
+				typed: true,
+			},
+			want: []map[string]interface{}{
+				{"foo": "first", "bar": 10},
+			},
+		},
+		{
+			name: "test_overflow",
+			args: args{
+				data:        []byte("foo,bar\nfirst,second,third,fourth\n,,fifth\nsixth,seventh"),
+				overflowKey: "_overflow",
+			},
+			want: []map[string]interface{}{
+				{"foo": "first", "bar": "second", "_overflow": []string{"third", "fourth"}},
+				{"foo": "", "bar": "", "_overflow": []string{"fifth"}},
+				{"foo": "sixth", "bar": "seventh"},
+			},
+		},
+		{
+			name: "test_overflow_header_conflict",
+			args: args{
+				data:        []byte("foo,_overflow\nfirst,second"),
+				overflowKey: "_overflow",
+			},
+			wantErr: ErrDuplicateHeader,
+		},
+		{
+			name: "test_strict",
+			args: args{
+				data:   []byte("foo,bar\nstring,int\nfirst,10\n# comment\nsecond,20"),
+				typed:  true,
+				strict: true,
+			},
+			want: []map[string]interface{}{
+				{"foo": "first", "bar": 10},
+				{"foo": "second", "bar": 20},
+			},
+		},
+		{
+			name: "test_strict_surplus",
+			args: args{
+				data:   []byte("foo,bar\nfirst,second\nthird,fourth,fifth"),
+				strict: true,
+			},
+			wantErr: ErrRecordWidth,
+		},
+		{
+			name: "test_strict_missing",
+			args: args{
+				data:   []byte("foo,bar\nstring,int\nfirst"),
+				typed:  true,
+				strict: true,
+			},
+			wantErr: ErrRecordWidth,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			csv := New(WithStrictRecordWidth(tt.args.strict), WithOverflowKey(tt.args.overflowKey))
+
+			var rslt []map[string]interface{}
+			var err error
+			if tt.args.typed {
+				rslt, err = csv.Typed(tt.args.data)
+			} else {
+				rslt, err = csv.Untyped(tt.args.data)
+			}
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("TestRecordWidth() received error = %v, want %v", err, tt.wantErr)
+			}
+
+			if !reflect.DeepEqual(rslt, tt.want) {
+				t.Errorf("TestRecordWidth() is not equal. \ngot = %+#v\nwant = %+#v", rslt, tt.want)
+			}
+		})
+	}
+}
+
+func TestCSV_Validate_recordWidth(t *testing.T) {
+	csv := New(WithStrictRecordWidth(true))
+
+	rslt, err := csv.Validate([]byte("foo,bar\nstring,int\nfirst,10\nsecond\nthird,30,40"))
+
+	want := []map[string]interface{}{{"foo": "first", "bar": 10}}
+	if !reflect.DeepEqual(rslt, want) {
+		t.Errorf("TestValidateRecordWidth() is not equal. \ngot = %+#v\nwant = %+#v", rslt, want)
+	}
+
+	var errs ParseErrors
+	if !errors.As(err, &errs) || len(errs) != 2 {
+		t.Fatalf("TestValidateRecordWidth() received error = %v, want 2 parse errors", err)
+	}
+
+	wantMsgs := []string{
+		"line 4, column 2: wrong number of cells: row has 1 cells, header has 2",
+		"line 5, column 3: wrong number of cells: row has 3 cells, header has 2",
+	}
+	for idx, msg := range wantMsgs {
+		if errs[idx].Error() != msg {
+			t.Errorf("TestValidateRecordWidth() is not equal. \ngot = %s\nwant = %s", errs[idx].Error(), msg)
+		}
+	}
+}
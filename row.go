@@ -0,0 +1,141 @@
+package csvx
+
+import (
+	"bytes"
+	"encoding/json"
+)
+
+// Row is a single csv row that keeps the columns in the order of the header.
+//
+// Columns that are missing in the csv row, because the row is shorter than the header, are not part of the row.
+type Row struct {
+	columns []string
+	values  []interface{}
+}
+
+// UntypedRows unmarshals the data into a slice of rows, which keep the column order of the header.
+func (c *CSVParser) UntypedRows(data []byte) ([]Row, error) {
+	return c.decodeAllRows(c.newDecoder(bytes.NewReader(data), false))
+}
+
+// TypedRows unmarshals the typed data into a slice of rows, which keep the column order of the header.
+//
+// The second row of the csv must contain the field types, otherwise an error is returned.
+func (c *CSVParser) TypedRows(data []byte) ([]Row, error) {
+	return c.decodeAllRows(c.newDecoder(bytes.NewReader(data), true))
+}
+
+// decodeAllRows collects all rows of the decoder.
+func (c *CSVParser) decodeAllRows(dec *Decoder) ([]Row, error) {
+	rslt := []Row{}
+	for dec.Next() {
+		rslt = append(rslt, dec.OrderedRow())
+	}
+	if err := dec.Err(); err != nil {
+		return nil, err
+	}
+
+	return rslt, nil
+}
+
+// add appends a column to the row.
+func (r *Row) add(name string, value interface{}) {
+	r.columns = append(r.columns, name)
+	r.values = append(r.values, value)
+}
+
+// addField appends a column to the row, or adds the value to the collected values of the column.
+func (r *Row) addField(f field, value interface{}) {
+	if !f.Collect {
+		r.add(f.Name, value)
+		return
+	}
+
+	idx, ok := r.lastIndex(f.Name)
+	if !ok {
+		r.add(f.Name, []interface{}{value})
+		return
+	}
+
+	r.values[idx] = append(r.values[idx].([]interface{}), value)
+}
+
+// Len returns the number of columns of the row.
+func (r Row) Len() int {
+	return len(r.columns)
+}
+
+// Columns returns the column names in the order of the header.
+func (r Row) Columns() []string {
+	return append([]string{}, r.columns...)
+}
+
+// At returns the value of the i-th column. It panics if i is out of range.
+func (r Row) At(i int) interface{} {
+	return r.values[i]
+}
+
+// Get returns the value of the named column and whether the column exists.
+// If several columns have the same name, the value of the last one is returned, as in the map of Typed and Untyped.
+func (r Row) Get(name string) (interface{}, bool) {
+	idx, ok := r.lastIndex(name)
+	if !ok {
+		return nil, false
+	}
+
+	return r.values[idx], true
+}
+
+// Map returns the row as map, as returned by Typed and Untyped.
+func (r Row) Map() map[string]interface{} {
+	rslt := make(map[string]interface{}, len(r.columns))
+	for idx, name := range r.columns {
+		rslt[name] = r.values[idx]
+	}
+
+	return rslt
+}
+
+// MarshalJSON encodes the row as json object with the keys in the order of the header.
+// If several columns have the same name, only the last one is encoded.
+func (r Row) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+
+	buf.WriteByte('{')
+	for idx, name := range r.columns {
+		if last, _ := r.lastIndex(name); last != idx {
+			continue
+		}
+
+		if buf.Len() > 1 {
+			buf.WriteByte(',')
+		}
+
+		key, err := json.Marshal(name)
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(key)
+		buf.WriteByte(':')
+
+		value, err := json.Marshal(r.values[idx])
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(value)
+	}
+	buf.WriteByte('}')
+
+	return buf.Bytes(), nil
+}
+
+// lastIndex returns the index of the last column with the given name.
+func (r Row) lastIndex(name string) (int, bool) {
+	for idx := len(r.columns) - 1; idx >= 0; idx-- {
+		if r.columns[idx] == name {
+			return idx, true
+		}
+	}
+
+	return -1, false
+}
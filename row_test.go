@@ -0,0 +1,108 @@
+package csvx
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestCSV_TypedRows(t *testing.T) {
+	csv := CSVParser{Comma: ',', Comment: '#', TrimLeadingSpace: true}
+
+	rows, err := csv.TypedRows([]byte(`
+		zeta,alpha,mid,names
+		string,int,*float64,"string,array"
+		first,10,,"a,b"
+		second,20,1.5`))
+	if err != nil {
+		t.Fatalf("TestTypedRows() received error = %v", err)
+	}
+
+	if len(rows) != 2 {
+		t.Fatalf("TestTypedRows() received %d rows, want 2", len(rows))
+	}
+
+	wantColumns := [][]string{
+		{"zeta", "alpha", "mid", "names"},
+		{"zeta", "alpha", "mid"},
+	}
+	for idx, row := range rows {
+		if !reflect.DeepEqual(row.Columns(), wantColumns[idx]) {
+			t.Errorf("TestTypedRows() columns are not equal. \ngot = %+#v\nwant = %+#v", row.Columns(), wantColumns[idx])
+		}
+		if row.Len() != len(wantColumns[idx]) {
+			t.Errorf("TestTypedRows() Len() = %d, want %d", row.Len(), len(wantColumns[idx]))
+		}
+	}
+
+	if rows[0].At(1) != 10 {
+		t.Errorf("TestTypedRows() At(1) = %#v, want 10", rows[0].At(1))
+	}
+
+	value, ok := rows[1].Get("mid")
+	if !ok || *value.(*float64) != 1.5 {
+		t.Errorf("TestTypedRows() Get(mid) = %#v, %v", value, ok)
+	}
+
+	if _, ok := rows[1].Get("names"); ok {
+		t.Errorf("TestTypedRows() Get(names) returned a missing column")
+	}
+
+	data, err := json.Marshal(rows)
+	if err != nil {
+		t.Fatalf("TestTypedRows() received error = %v", err)
+	}
+
+	want := `[{"zeta":"first","alpha":10,"mid":null,"names":["a","b"]},{"zeta":"second","alpha":20,"mid":1.5}]`
+	if string(data) != want {
+		t.Errorf("TestTypedRows() json is not equal. \ngot = %s\nwant = %s", data, want)
+	}
+}
+
+func TestCSV_UntypedRows(t *testing.T) {
+	csv := CSVParser{Comma: ';'}
+
+	rows, err := csv.UntypedRows([]byte("b;a;b\n1;2;3"))
+	if err != nil {
+		t.Fatalf("TestUntypedRows() received error = %v", err)
+	}
+
+	wantMap := map[string]interface{}{"b": "3", "a": "2"}
+	if !reflect.DeepEqual(rows[0].Map(), wantMap) {
+		t.Errorf("TestUntypedRows() map is not equal. \ngot = %+#v\nwant = %+#v", rows[0].Map(), wantMap)
+	}
+
+	value, _ := rows[0].Get("b")
+	if value != "3" {
+		t.Errorf("TestUntypedRows() Get(b) = %#v, want 3", value)
+	}
+
+	data, err := json.Marshal(rows[0])
+	if err != nil {
+		t.Fatalf("TestUntypedRows() received error = %v", err)
+	}
+
+	want := `{"a":"2","b":"3"}`
+	if string(data) != want {
+		t.Errorf("TestUntypedRows() json is not equal. \ngot = %s\nwant = %s", data, want)
+	}
+}
+
+func TestCSV_Marshal_rows(t *testing.T) {
+	csv := CSVParser{Comma: ',', Comment: '#'}
+	data := []byte("zeta,alpha,names\nstring,*int,\"string,array\"\nfirst,,\"a,b\"\nsecond,20,\n")
+
+	rows, err := csv.TypedRows(data)
+	if err != nil {
+		t.Fatalf("TestMarshalRows() received error = %v", err)
+	}
+
+	encoded, err := csv.Marshal(rows)
+	if err != nil {
+		t.Fatalf("TestMarshalRows() received error = %v", err)
+	}
+
+	if string(encoded) != string(data) {
+		t.Errorf("TestMarshalRows() is not equal. \ngot = %q\nwant = %q", encoded, data)
+	}
+}
@@ -0,0 +1,196 @@
+package csvx
+
+import (
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"io"
+	"strings"
+)
+
+var ErrSniffFailed = errors.New("unable to sniff csv dialect")
+
+// sniffSampleSize is the number of bytes that are examined, if the dialect is sniffed automatically.
+const sniffSampleSize = 64 << 10
+
+// sniffDelimiters contains the delimiters Sniff detects, in the order of precedence.
+var sniffDelimiters = []rune{',', ';', '\t', '|'}
+
+// sniffComments contains the comment characters Sniff detects, in the order of precedence.
+var sniffComments = []rune{'#', '%'}
+
+// Dialect describes the format of a csv, as detected by Sniff.
+type Dialect struct {
+	// Comma is the rune with which the entries are separated from each other.
+	Comma rune
+	// Comment is the rune that marks comment lines. It is 0 if the sample contains no comment lines.
+	Comment rune
+	// HasTypeRow reports whether the row after the header names consists of type names only.
+	HasTypeRow bool
+	// LineEnding is the line break of the csv, "\n", "\r\n" or "\r".
+	LineEnding string
+}
+
+// Sniff detects the dialect of the csv sample using the default settings.
+//
+// See CSVParser.Sniff for details.
+func Sniff(sample []byte) (Dialect, error) {
+	return (&CSVParser{}).Sniff(sample)
+}
+
+// Sniff detects the dialect of the csv sample, which may be the beginning of a larger csv.
+//
+// The delimiter is the one of ',', ';', '\t' and '|' that splits the rows into the same number of cells,
+// lines starting with '#' or '%' are comment lines. The row after the header is a type row, if all of its cells
+// are builtin types or custom types registered for the parser. ErrSniffFailed is returned if the sample
+// contains no row. All lines of the sample are examined, a sample that is cut from a larger csv should
+// therefore end with a line break.
+func (c *CSVParser) Sniff(sample []byte) (Dialect, error) {
+	decoded, err := io.ReadAll(c.decodeReader(bytes.NewReader(sample)))
+	if err != nil {
+		return Dialect{}, err
+	}
+
+	return c.sniff(decoded, false)
+}
+
+// sniff detects the dialect of the UTF-8 sample.
+// truncated reports whether the sample was cut from a larger input, so that its last line may be incomplete.
+func (c *CSVParser) sniff(sample []byte, truncated bool) (Dialect, error) {
+	text := string(sample)
+
+	dialect := Dialect{
+		Comma:      ',',
+		LineEnding: "\n",
+	}
+	if idx := strings.IndexAny(text, "\r\n"); idx >= 0 {
+		dialect.LineEnding = text[idx : idx+1]
+		if strings.HasPrefix(text[idx:], "\r\n") {
+			dialect.LineEnding = "\r\n"
+		}
+	}
+
+	lines := strings.Split(strings.ReplaceAll(text, "\r\n", "\n"), "\n")
+	if dialect.LineEnding == "\r" {
+		lines = strings.Split(text, "\r")
+	}
+	if truncated && len(lines) > 1 && !strings.HasSuffix(text, dialect.LineEnding) {
+		// the last line of a truncated sample may be incomplete
+		lines = lines[:len(lines)-1]
+	}
+
+	dialect.Comment = sniffComment(lines)
+
+	content := []string{}
+	for _, line := range lines {
+		if line == "" || (dialect.Comment != 0 && strings.HasPrefix(line, string(dialect.Comment))) {
+			continue
+		}
+		content = append(content, line)
+	}
+	if len(content) == 0 {
+		return Dialect{}, ErrSniffFailed
+	}
+
+	var records [][]string
+	bestRows, bestCells := 0, 0
+	for _, delimiter := range sniffDelimiters {
+		candidate := c.sniffRecords(content, delimiter)
+		rows, cells := consistentRows(candidate)
+		if cells < 2 {
+			continue
+		}
+
+		if rows > bestRows || (rows == bestRows && cells > bestCells) {
+			dialect.Comma, records = delimiter, candidate
+			bestRows, bestCells = rows, cells
+		}
+	}
+
+	if len(records) > 1 {
+		dialect.HasTypeRow = true
+		for _, cell := range records[1] {
+			if !c.isTypeName(cell) {
+				dialect.HasTypeRow = false
+				break
+			}
+		}
+	}
+
+	return dialect, nil
+}
+
+// sniffComment returns the comment character that starts the most lines, as long as it does not start all lines.
+func sniffComment(lines []string) rune {
+	comment, best := rune(0), 0
+	for _, candidate := range sniffComments {
+		count, other := 0, false
+		for _, line := range lines {
+			switch {
+			case strings.HasPrefix(line, string(candidate)):
+				count++
+			case line != "":
+				other = true
+			}
+		}
+
+		if other && count > best {
+			comment, best = candidate, count
+		}
+	}
+
+	return comment
+}
+
+// sniffRecords reads the lines with the delimiter. Lines that cannot be read end the records.
+func (c *CSVParser) sniffRecords(lines []string, delimiter rune) [][]string {
+	reader := csv.NewReader(strings.NewReader(strings.Join(lines, "\n")))
+	reader.Comma = delimiter
+	reader.FieldsPerRecord = -1
+	reader.LazyQuotes = true
+
+	records := [][]string{}
+	for {
+		record, err := reader.Read()
+		if err != nil {
+			return records
+		}
+		records = append(records, record)
+	}
+}
+
+// consistentRows returns the number of cells of the header and the number of records that have as many cells.
+func consistentRows(records [][]string) (int, int) {
+	if len(records) == 0 {
+		return 0, 0
+	}
+
+	rows := 0
+	for _, record := range records {
+		if len(record) == len(records[0]) {
+			rows++
+		}
+	}
+
+	return rows, len(records[0])
+}
+
+// isTypeName reports whether value is a type of the type row, with an optional pointer prefix and default value.
+func (c *CSVParser) isTypeName(value string) bool {
+	typ, _, _ := splitDefault(value)
+	format := baseFormat(strings.TrimPrefix(typ, "*"))
+	if _, ok := formatTypes[format]; ok || format == "json" {
+		return true
+	}
+
+	_, ok := c.lookupType(strings.TrimSuffix(format, ",array"))
+	return ok
+}
+
+// applyDialect replaces the delimiter and, if the dialect has one, the comment character of the parser.
+func (c *CSVParser) applyDialect(dialect Dialect) {
+	c.Comma = dialect.Comma
+	if dialect.Comment != 0 {
+		c.Comment = dialect.Comment
+	}
+}
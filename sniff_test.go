@@ -0,0 +1,165 @@
+package csvx
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestCSV_Sniff(t *testing.T) {
+	tests := []struct {
+		name    string
+		sample  []byte
+		want    Dialect
+		wantErr error
+	}{
+		{
+			name:   "test_comma",
+			sample: []byte("foo,bar\nhello,world\n"),
+			want:   Dialect{Comma: ',', LineEnding: "\n"},
+		},
+		{
+			name:   "test_semicolon_with_decimal_comma",
+			sample: []byte("name;price\r\nstring;*float64\r\nfirst;1,5\r\nsecond;2,25\r\n"),
+			want:   Dialect{Comma: ';', HasTypeRow: true, LineEnding: "\r\n"},
+		},
+		{
+			name:   "test_tab",
+			sample: []byte("id\ttags\nint64\tstring,array(|)\n1\ta|b\n"),
+			want:   Dialect{Comma: '\t', HasTypeRow: true, LineEnding: "\n"},
+		},
+		{
+			name:   "test_pipe_with_quotes",
+			sample: []byte("a|b|c\n\"x|y\"|2|3\n4|5|6"),
+			want:   Dialect{Comma: '|', LineEnding: "\n"},
+		},
+		{
+			name:   "test_comment",
+			sample: []byte("% exported data\nid;status\nint64=1;enum(draft|published)\n% end\n"),
+			want:   Dialect{Comma: ';', Comment: '%', HasTypeRow: true, LineEnding: "\n"},
+		},
+		{
+			name:   "test_unknown_type",
+			sample: []byte("id,name\nint64,text\n"),
+			want:   Dialect{Comma: ',', LineEnding: "\n"},
+		},
+		{
+			name:   "test_inconsistent_last_line",
+			sample: []byte("a;b\n1;2\n3;4\n5,6,7,8"),
+			want:   Dialect{Comma: ';', LineEnding: "\n"},
+		},
+		{
+			name:   "test_no_trailing_line_break",
+			sample: []byte("a;b\nstring;int"),
+			want:   Dialect{Comma: ';', HasTypeRow: true, LineEnding: "\n"},
+		},
+		{
+			name:   "test_single_column",
+			sample: []byte("name\rfirst\rsecond\r"),
+			want:   Dialect{Comma: ',', LineEnding: "\r"},
+		},
+		{
+			name:   "test_bom",
+			sample: []byte("\xEF\xBB\xBF#comment\nfoo;bar\n"),
+			want:   Dialect{Comma: ';', Comment: '#', LineEnding: "\n"},
+		},
+		{
+			name:    "test_empty",
+			sample:  []byte("\r\n\r\n"),
+			wantErr: ErrSniffFailed,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rslt, err := Sniff(tt.sample)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("TestSniff() received error = %v, want %v", err, tt.wantErr)
+			}
+
+			if !reflect.DeepEqual(rslt, tt.want) {
+				t.Errorf("TestSniff() is not equal. \ngot = %+#v\nwant = %+#v", rslt, tt.want)
+			}
+		})
+	}
+}
+
+func TestCSV_sniff_truncated(t *testing.T) {
+	tests := []struct {
+		name      string
+		sample    []byte
+		truncated bool
+		want      Dialect
+	}{
+		{
+			name:   "test_complete",
+			sample: []byte("a;b\nstring;int"),
+			want:   Dialect{Comma: ';', HasTypeRow: true, LineEnding: "\n"},
+		},
+		{
+			name:      "test_truncated",
+			sample:    []byte("a;b\nstring;int"),
+			truncated: true,
+			want:      Dialect{Comma: ';', LineEnding: "\n"},
+		},
+		{
+			name:      "test_truncated_at_line_break",
+			sample:    []byte("a;b\nstring;int\n"),
+			truncated: true,
+			want:      Dialect{Comma: ';', HasTypeRow: true, LineEnding: "\n"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rslt, err := (&CSVParser{}).sniff(tt.sample, tt.truncated)
+			if err != nil {
+				t.Fatalf("TestSniffTruncated() received error = %v", err)
+			}
+
+			if !reflect.DeepEqual(rslt, tt.want) {
+				t.Errorf("TestSniffTruncated() is not equal. \ngot = %+#v\nwant = %+#v", rslt, tt.want)
+			}
+		})
+	}
+}
+
+func TestCSV_Sniff_customType(t *testing.T) {
+	csv := New(WithType("sku", parseTestSKU))
+
+	rslt, err := csv.Sniff([]byte("id;code\nint64;*sku\n"))
+	if err != nil {
+		t.Fatalf("TestSniffCustomType() received error = %v", err)
+	}
+
+	if !rslt.HasTypeRow {
+		t.Errorf("TestSniffCustomType() did not detect the type row")
+	}
+}
+
+func TestCSV_Typed_sniffDialect(t *testing.T) {
+	csv := New(WithSniffDialect(true))
+
+	rslt, err := csv.Typed([]byte("% comment\nname;count\nstring;int\n% another comment\nfirst;1\n" + strings.Repeat("next;2\n", 10000)))
+	if err != nil {
+		t.Fatalf("TestTypedSniffDialect() received error = %v", err)
+	}
+
+	if len(rslt) != 10001 {
+		t.Fatalf("TestTypedSniffDialect() received %d rows, want 10001", len(rslt))
+	}
+
+	want := map[string]interface{}{"name": "first", "count": 1}
+	if !reflect.DeepEqual(rslt[0], want) {
+		t.Errorf("TestTypedSniffDialect() is not equal. \ngot = %+#v\nwant = %+#v", rslt[0], want)
+	}
+
+	untyped, err := csv.Untyped([]byte("foo\tbar\nhello\tworld"))
+	if err != nil {
+		t.Fatalf("TestTypedSniffDialect() received error = %v", err)
+	}
+
+	wantUntyped := []map[string]interface{}{{"foo": "hello", "bar": "world"}}
+	if !reflect.DeepEqual(untyped, wantUntyped) {
+		t.Errorf("TestTypedSniffDialect() is not equal. \ngot = %+#v\nwant = %+#v", untyped, wantUntyped)
+	}
+}
@@ -0,0 +1,89 @@
+package csvx
+
+import (
+	"reflect"
+	"time"
+)
+
+// timeLayouts contains the default layouts of the time types, which follow the formats of RFC 3339.
+var timeLayouts = map[string]string{
+	// full-date
+	"date": "2006-01-02",
+	// partial-time
+	"time": "15:04:05.999999999",
+	// date-time
+	"datetime": time.RFC3339Nano,
+}
+
+// localDateTimeLayout is the layout of default datetime values without offset, which are read in the time zone
+// of the parser.
+const localDateTimeLayout = "2006-01-02T15:04:05.999999999"
+
+// location returns the time zone for time values without offset.
+func (c *CSVParser) location() *time.Location {
+	if c.Location == nil {
+		return time.UTC
+	}
+
+	return c.Location
+}
+
+// timeType returns the conversion function and the go type for the time types.
+// layout overrides the default layout of the type, it is ignored for durations.
+func (c *CSVParser) timeType(name, layout string) (TypeFunc, reflect.Type, bool) {
+	if name == "duration" {
+		return func(value string) (interface{}, error) {
+			if value == "" {
+				return time.Duration(0), nil
+			}
+
+			return time.ParseDuration(value)
+		}, reflect.TypeOf(time.Duration(0)), true
+	}
+
+	defaultLayout, ok := timeLayouts[name]
+	if !ok {
+		return nil, nil, false
+	}
+	fallback := false
+	if layout == "" {
+		layout = defaultLayout
+		fallback = name == "datetime"
+	}
+
+	return func(value string) (interface{}, error) {
+		if value == "" {
+			return time.Time{}, nil
+		}
+
+		t, err := time.ParseInLocation(layout, value, c.location())
+		if err != nil && fallback {
+			// the offset of default datetime values is optional
+			if local, localErr := time.ParseInLocation(localDateTimeLayout, value, c.location()); localErr == nil {
+				return local, nil
+			}
+		}
+
+		return t, err
+	}, reflect.TypeOf(time.Time{}), true
+}
+
+// formatTime converts a time.Time or time.Duration into its csv representation.
+// Times are converted into the time zone of the parser first.
+func (c *CSVParser) formatTime(value interface{}, name, layout string) (string, bool) {
+	switch v := value.(type) {
+	case time.Duration:
+		return v.String(), true
+	case time.Time:
+		if layout == "" {
+			layout = timeLayouts[name]
+		}
+		if layout == "" {
+			layout = timeLayouts["datetime"]
+		}
+
+		return v.In(c.location()).Format(layout), true
+	default:
+		return "", false
+	}
+}
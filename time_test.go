@@ -0,0 +1,253 @@
+package csvx
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestCSV_toTyped_time(t *testing.T) {
+	berlin := time.FixedZone("CET", 3600)
+
+	type args struct {
+		value, format string
+		isPointerType bool
+		location      *time.Location
+	}
+	tests := []struct {
+		name    string
+		args    args
+		want    interface{}
+		wantErr bool
+	}{
+		{
+			name: "test_datetime",
+			args: args{
+				value:  "2021-03-04T05:06:07.5+02:00",
+				format: "datetime",
+			},
+			want: time.Date(2021, 3, 4, 5, 6, 7, 500000000, time.FixedZone("", 7200)),
+		},
+		{
+			name: "test_datetime_utc",
+			args: args{
+				value:  "2021-03-04T05:06:07Z",
+				format: "datetime",
+			},
+			want: time.Date(2021, 3, 4, 5, 6, 7, 0, time.UTC),
+		},
+		{
+			name: "test_datetime_without_offset",
+			args: args{
+				value:  "2021-03-04T05:06:07",
+				format: "datetime",
+			},
+			want: time.Date(2021, 3, 4, 5, 6, 7, 0, time.UTC),
+		},
+		{
+			name: "test_datetime_without_offset_location",
+			args: args{
+				value:         "2021-03-04T05:06:07.25",
+				format:        "datetime",
+				isPointerType: true,
+				location:      berlin,
+			},
+			want: func() *time.Time { v := time.Date(2021, 3, 4, 5, 6, 7, 250000000, berlin); return &v }(),
+		},
+		{
+			name: "test_datetime_custom_layout_without_offset",
+			args: args{
+				value:  "2021-03-04T05:06:07",
+				format: "datetime(2006-01-02T15:04:05Z07:00)",
+			},
+			wantErr: true,
+		},
+		{
+			name: "test_date",
+			args: args{
+				value:  "2021-03-04",
+				format: "date",
+			},
+			want: time.Date(2021, 3, 4, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name: "test_date_location",
+			args: args{
+				value:    "2021-03-04",
+				format:   "date",
+				location: berlin,
+			},
+			want: time.Date(2021, 3, 4, 0, 0, 0, 0, berlin),
+		},
+		{
+			name: "test_time",
+			args: args{
+				value:  "05:06:07",
+				format: "time",
+			},
+			want: time.Date(0, 1, 1, 5, 6, 7, 0, time.UTC),
+		},
+		{
+			name: "test_time_layout",
+			args: args{
+				value:    "2021-03-04 05:06",
+				format:   "time(2006-01-02 15:04)",
+				location: berlin,
+			},
+			want: time.Date(2021, 3, 4, 5, 6, 0, 0, berlin),
+		},
+		{
+			name: "test_time_empty",
+			args: args{
+				value:  "",
+				format: "datetime",
+			},
+			want: time.Time{},
+		},
+		{
+			name: "test_time_invalid",
+			args: args{
+				value:  "2021-03-04",
+				format: "datetime",
+			},
+			wantErr: true,
+		},
+		{
+			name: "test_duration",
+			args: args{
+				value:  "1h30m",
+				format: "duration",
+			},
+			want: 90 * time.Minute,
+		},
+		{
+			name: "test_duration_invalid",
+			args: args{
+				value:  "10",
+				format: "duration",
+			},
+			wantErr: true,
+		},
+		{
+			name: "test_date_array",
+			args: args{
+				value:  "2021-03-04, 2021-03-05",
+				format: "date,array",
+			},
+			want: []time.Time{
+				time.Date(2021, 3, 4, 0, 0, 0, 0, time.UTC),
+				time.Date(2021, 3, 5, 0, 0, 0, 0, time.UTC),
+			},
+		},
+		{
+			name: "test_date_layout_array",
+			args: args{
+				value:  "04.03.2021,05.03.2021",
+				format: "date(02.01.2006),array",
+			},
+			want: []time.Time{
+				time.Date(2021, 3, 4, 0, 0, 0, 0, time.UTC),
+				time.Date(2021, 3, 5, 0, 0, 0, 0, time.UTC),
+			},
+		},
+		{
+			name: "test_duration_array",
+			args: args{
+				value:  "1s,2m",
+				format: "duration,array",
+			},
+			want: []time.Duration{time.Second, 2 * time.Minute},
+		},
+		{
+			name: "test_duration_array_empty",
+			args: args{
+				value:  "",
+				format: "duration,array",
+			},
+			want: []time.Duration{},
+		},
+
+		// pointer
+
+		{
+			name: "test_date_ptr",
+			args: args{
+				value:         "2021-03-04",
+				format:        "date",
+				isPointerType: true,
+			},
+			want: func(t time.Time) *time.Time { return &t }(time.Date(2021, 3, 4, 0, 0, 0, 0, time.UTC)),
+		},
+		{
+			name: "test_date_ptr_empty",
+			args: args{
+				value:         "",
+				format:        "date",
+				isPointerType: true,
+			},
+			want: nil,
+		},
+		{
+			name: "test_duration_array_ptr",
+			args: args{
+				value:         "1s",
+				format:        "duration,array",
+				isPointerType: true,
+			},
+			want: &[]time.Duration{time.Second},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			csv := CSVParser{Comma: ',', Comment: '#', TrimLeadingSpace: true, Location: tt.args.location}
+
+			rslt, err := csv.toTyped(tt.args.value, tt.args.format, tt.args.isPointerType)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("TestToTypedTime() received error = %v", err)
+			}
+
+			if !reflect.DeepEqual(rslt, tt.want) {
+				t.Errorf("TestToTypedTime() is not equal. \ngot = %+#v\nwant = %+#v", rslt, tt.want)
+			}
+		})
+	}
+}
+
+func TestCSV_Marshal_time(t *testing.T) {
+	type event struct {
+		Start    time.Time     `csv:"start"`
+		Duration time.Duration `csv:"duration"`
+		End      *time.Time    `csv:"end"`
+	}
+
+	start := time.Date(2021, 3, 4, 5, 6, 7, 0, time.UTC)
+	data := []event{
+		{Start: start, Duration: time.Hour},
+		{Start: start.Add(time.Hour), Duration: time.Minute, End: &start},
+	}
+
+	csv := CSVParser{}
+
+	encoded, err := csv.Marshal(data)
+	if err != nil {
+		t.Fatalf("TestMarshalTime() received error = %v", err)
+	}
+
+	want := "start,duration,end\n" +
+		"datetime,duration,*datetime\n" +
+		"2021-03-04T05:06:07Z,1h0m0s,\n" +
+		"2021-03-04T06:06:07Z,1m0s,2021-03-04T05:06:07Z\n"
+	if string(encoded) != want {
+		t.Errorf("TestMarshalTime() is not equal. \ngot = %q\nwant = %q", encoded, want)
+	}
+
+	var rslt []event
+	err = csv.Unmarshal(encoded, &rslt)
+	if err != nil {
+		t.Fatalf("TestMarshalTime() received error = %v", err)
+	}
+
+	if !reflect.DeepEqual(rslt, data) {
+		t.Errorf("TestMarshalTime() is not equal. \ngot = %+#v\nwant = %+#v", rslt, data)
+	}
+}
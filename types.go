@@ -0,0 +1,276 @@
+package csvx
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"reflect"
+	"strings"
+	"sync"
+	"time"
+)
+
+var ErrOnlyOneRowIsAllowedForArray = errors.New("only one row is allowed for array types")
+
+// formatTypes maps the supported type names of the type row to the go type toTyped returns for them.
+//
+// json is not part of the list, since it can be unmarshalled into any go type.
+var formatTypes = map[string]reflect.Type{
+	"string":         reflect.TypeOf(""),
+	"int64":          reflect.TypeOf(int64(0)),
+	"int":            reflect.TypeOf(int(0)),
+	"float64":        reflect.TypeOf(float64(0)),
+	"bool":           reflect.TypeOf(false),
+	"int8":           reflect.TypeOf(int8(0)),
+	"int16":          reflect.TypeOf(int16(0)),
+	"int32":          reflect.TypeOf(int32(0)),
+	"uint":           reflect.TypeOf(uint(0)),
+	"uint8":          reflect.TypeOf(uint8(0)),
+	"uint16":         reflect.TypeOf(uint16(0)),
+	"uint32":         reflect.TypeOf(uint32(0)),
+	"uint64":         reflect.TypeOf(uint64(0)),
+	"float32":        reflect.TypeOf(float32(0)),
+	"bigint":         reflect.TypeOf(&big.Int{}),
+	"decimal":        reflect.TypeOf(&big.Rat{}),
+	"bigfloat":       reflect.TypeOf(&big.Float{}),
+	"time":           reflect.TypeOf(time.Time{}),
+	"date":           reflect.TypeOf(time.Time{}),
+	"datetime":       reflect.TypeOf(time.Time{}),
+	"duration":       reflect.TypeOf(time.Duration(0)),
+	"enum":           reflect.TypeOf(""),
+	"string,array":   reflect.TypeOf([]string{}),
+	"int64,array":    reflect.TypeOf([]int64{}),
+	"int,array":      reflect.TypeOf([]int{}),
+	"float64,array":  reflect.TypeOf([]float64{}),
+	"bool,array":     reflect.TypeOf([]bool{}),
+	"int8,array":     reflect.TypeOf([]int8{}),
+	"int16,array":    reflect.TypeOf([]int16{}),
+	"int32,array":    reflect.TypeOf([]int32{}),
+	"uint,array":     reflect.TypeOf([]uint{}),
+	"uint8,array":    reflect.TypeOf([]uint8{}),
+	"uint16,array":   reflect.TypeOf([]uint16{}),
+	"uint32,array":   reflect.TypeOf([]uint32{}),
+	"uint64,array":   reflect.TypeOf([]uint64{}),
+	"float32,array":  reflect.TypeOf([]float32{}),
+	"bigint,array":   reflect.TypeOf([]*big.Int{}),
+	"decimal,array":  reflect.TypeOf([]*big.Rat{}),
+	"bigfloat,array": reflect.TypeOf([]*big.Float{}),
+	"time,array":     reflect.TypeOf([]time.Time{}),
+	"date,array":     reflect.TypeOf([]time.Time{}),
+	"datetime,array": reflect.TypeOf([]time.Time{}),
+	"duration,array": reflect.TypeOf([]time.Duration{}),
+	"enum,array":     reflect.TypeOf([]string{}),
+}
+
+// typeFormats maps go types to the type name the encoder uses for them.
+// It is the inverse of formatTypes, for go types with several type names the most general one is used.
+// Big numbers are pointers, so their pointer type is used to keep nil values.
+var typeFormats = map[reflect.Type]string{
+	reflect.TypeOf(""):                "string",
+	reflect.TypeOf(int64(0)):          "int64",
+	reflect.TypeOf(int(0)):            "int",
+	reflect.TypeOf(float64(0)):        "float64",
+	reflect.TypeOf(false):             "bool",
+	reflect.TypeOf(int8(0)):           "int8",
+	reflect.TypeOf(int16(0)):          "int16",
+	reflect.TypeOf(int32(0)):          "int32",
+	reflect.TypeOf(uint(0)):           "uint",
+	reflect.TypeOf(uint8(0)):          "uint8",
+	reflect.TypeOf(uint16(0)):         "uint16",
+	reflect.TypeOf(uint32(0)):         "uint32",
+	reflect.TypeOf(uint64(0)):         "uint64",
+	reflect.TypeOf(float32(0)):        "float32",
+	reflect.TypeOf(&big.Int{}):        "*bigint",
+	reflect.TypeOf(&big.Rat{}):        "*decimal",
+	reflect.TypeOf(&big.Float{}):      "*bigfloat",
+	reflect.TypeOf(time.Time{}):       "datetime",
+	reflect.TypeOf(time.Duration(0)):  "duration",
+	reflect.TypeOf([]string{}):        "string,array",
+	reflect.TypeOf([]int64{}):         "int64,array",
+	reflect.TypeOf([]int{}):           "int,array",
+	reflect.TypeOf([]float64{}):       "float64,array",
+	reflect.TypeOf([]bool{}):          "bool,array",
+	reflect.TypeOf([]int8{}):          "int8,array",
+	reflect.TypeOf([]int16{}):         "int16,array",
+	reflect.TypeOf([]int32{}):         "int32,array",
+	reflect.TypeOf([]uint{}):          "uint,array",
+	reflect.TypeOf([]uint8{}):         "uint8,array",
+	reflect.TypeOf([]uint16{}):        "uint16,array",
+	reflect.TypeOf([]uint32{}):        "uint32,array",
+	reflect.TypeOf([]uint64{}):        "uint64,array",
+	reflect.TypeOf([]float32{}):       "float32,array",
+	reflect.TypeOf([]*big.Int{}):      "bigint,array",
+	reflect.TypeOf([]*big.Rat{}):      "decimal,array",
+	reflect.TypeOf([]*big.Float{}):    "bigfloat,array",
+	reflect.TypeOf([]time.Time{}):     "datetime,array",
+	reflect.TypeOf([]time.Duration{}): "duration,array",
+}
+
+// TypeFunc converts the value of a cell into a custom type.
+//
+// For non pointer types, the function is also called for empty cells, so it can return a zero value.
+type TypeFunc func(value string) (interface{}, error)
+
+var (
+	// globalTypesMtx guards globalTypes.
+	globalTypesMtx sync.RWMutex
+	// globalTypes contains the custom types registered with RegisterType.
+	globalTypes = map[string]TypeFunc{}
+)
+
+// RegisterType registers a custom type name for the type row, which is available for all parsers.
+//
+// The type can also be used as pointer ("*name") and array ("name,array"). Array cells are converted
+// into a []interface{} that contains the converted elements.
+//
+// RegisterType panics if the name is empty, contains '*' or ',', is a builtin type or if fn is nil.
+// Registering a name again replaces the previous function.
+func RegisterType(name string, fn TypeFunc) {
+	checkTypeName(name, fn)
+
+	globalTypesMtx.Lock()
+	defer globalTypesMtx.Unlock()
+
+	globalTypes[name] = fn
+}
+
+// RegisterType registers a custom type name for the type row, which is only available for this parser.
+// Types registered on the parser take precedence over types registered with the global RegisterType.
+//
+// Copies of the parser, which share the registered types, are not affected.
+//
+// See RegisterType for details.
+func (c *CSVParser) RegisterType(name string, fn TypeFunc) {
+	checkTypeName(name, fn)
+
+	// the map is copied, since copies of the parser may use it at the same time
+	types := make(map[string]TypeFunc, len(c.types)+1)
+	for key, value := range c.types {
+		types[key] = value
+	}
+	types[name] = fn
+
+	c.types = types
+}
+
+// checkTypeName panics if the custom type cannot be registered.
+func checkTypeName(name string, fn TypeFunc) {
+	if fn == nil {
+		panic("csvx: RegisterType function is nil")
+	}
+
+	if name == "" || strings.ContainsAny(name, "*,") {
+		panic(fmt.Sprintf("csvx: invalid type name %q", name))
+	}
+
+	if _, ok := formatTypes[name]; ok || name == "json" {
+		panic(fmt.Sprintf("csvx: cannot override builtin type %q", name))
+	}
+}
+
+// lookupType returns the custom type with the given name.
+func (c *CSVParser) lookupType(name string) (TypeFunc, bool) {
+	if fn, ok := c.types[name]; ok {
+		return fn, true
+	}
+
+	globalTypesMtx.RLock()
+	defer globalTypesMtx.RUnlock()
+
+	fn, ok := globalTypes[name]
+	return fn, ok
+}
+
+// splitFormat splits a type name with an argument like "time(15:04)" into the name and the argument.
+func splitFormat(format string) (string, string) {
+	idx := strings.Index(format, "(")
+	if idx < 0 || !strings.HasSuffix(format, ")") {
+		return format, ""
+	}
+
+	return format[:idx], format[idx+1 : len(format)-1]
+}
+
+// baseFormat removes the arguments from the type name, so that it can be looked up in formatTypes.
+// e.g. "time(15:04),array(|)" becomes "time,array".
+func baseFormat(format string) string {
+	format, _, _ = splitArraySeparator(format)
+	name := strings.TrimSuffix(format, ",array")
+	base, _ := splitFormat(name)
+	if name != format {
+		return base + ",array"
+	}
+
+	return base
+}
+
+// customToTyped converts the value with a custom type, including its pointer and array variants.
+func (c *CSVParser) customToTyped(value, format string, isPointer bool) (interface{}, error) {
+	name := strings.TrimSuffix(format, ",array")
+
+	fn, ok := c.lookupType(name)
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUnsupportedType, format)
+	}
+
+	return c.convertWith(value, fn, nil, name != format, isPointer)
+}
+
+// convertWith converts the value with fn, including its pointer and array variants.
+//
+// Arrays are returned as slice of elemType. If elemType is nil, a []interface{} is returned.
+func (c *CSVParser) convertWith(value string, fn TypeFunc, elemType reflect.Type, isArray, isPointer bool) (interface{}, error) {
+	if value == "" && isPointer {
+		return nil, nil
+	}
+
+	var typed interface{}
+	if !isArray {
+		var err error
+		typed, err = fn(value)
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		if elemType == nil {
+			elemType = reflect.TypeOf((*interface{})(nil)).Elem()
+		}
+
+		values := reflect.MakeSlice(reflect.SliceOf(elemType), 0, 0)
+		if value != "" {
+			records, err := c.readArray(value)
+			if err != nil {
+				return nil, err
+			}
+
+			//Check if we only have one row. If not return error
+			if len(records) > 1 {
+				return nil, ErrOnlyOneRowIsAllowedForArray
+			}
+
+			for _, record := range records {
+				for _, v := range record {
+					vi, err := fn(v)
+					if err != nil {
+						return nil, err
+					}
+
+					elem := reflect.Zero(elemType)
+					if vi != nil {
+						elem = reflect.ValueOf(vi)
+					}
+					values = reflect.Append(values, elem)
+				}
+			}
+		}
+		typed = values.Interface()
+	}
+
+	if isPointer && typed != nil {
+		p := reflect.New(reflect.TypeOf(typed))
+		p.Elem().Set(reflect.ValueOf(typed))
+		return p.Interface(), nil
+	}
+
+	return typed, nil
+}
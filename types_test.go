@@ -0,0 +1,230 @@
+package csvx
+
+import (
+	"errors"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+)
+
+type testSKU string
+
+type testMoney struct {
+	Cents int64
+}
+
+func parseTestSKU(value string) (interface{}, error) {
+	if value != "" && !strings.HasPrefix(value, "SKU-") {
+		return nil, errors.New("invalid sku")
+	}
+
+	return testSKU(value), nil
+}
+
+func parseTestMoney(value string) (interface{}, error) {
+	if value == "" {
+		return testMoney{}, nil
+	}
+
+	f, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return nil, err
+	}
+
+	return testMoney{Cents: int64(f * 100)}, nil
+}
+
+func TestCSV_RegisterType(t *testing.T) {
+	RegisterType("test_money", parseTestMoney)
+
+	type args struct {
+		value, format string
+		isPointerType bool
+	}
+	tests := []struct {
+		name    string
+		args    args
+		want    interface{}
+		wantErr bool
+	}{
+		{
+			name: "test_sku",
+			args: args{
+				value:  "SKU-1",
+				format: "sku",
+			},
+			want: testSKU("SKU-1"),
+		},
+		{
+			name: "test_sku_empty",
+			args: args{
+				value:  "",
+				format: "sku",
+			},
+			want: testSKU(""),
+		},
+		{
+			name: "test_sku_invalid",
+			args: args{
+				value:  "1",
+				format: "sku",
+			},
+			wantErr: true,
+		},
+		{
+			name: "test_sku_ptr",
+			args: args{
+				value:         "SKU-1",
+				format:        "sku",
+				isPointerType: true,
+			},
+			want: func(s testSKU) *testSKU { return &s }("SKU-1"),
+		},
+		{
+			name: "test_sku_ptr_empty",
+			args: args{
+				value:         "",
+				format:        "sku",
+				isPointerType: true,
+			},
+			want: nil,
+		},
+		{
+			name: "test_sku_array",
+			args: args{
+				value:  "SKU-1, SKU-2",
+				format: "sku,array",
+			},
+			want: []interface{}{testSKU("SKU-1"), testSKU("SKU-2")},
+		},
+		{
+			name: "test_sku_array_empty",
+			args: args{
+				value:  "",
+				format: "sku,array",
+			},
+			want: []interface{}{},
+		},
+		{
+			name: "test_sku_array_ptr",
+			args: args{
+				value:         "SKU-1",
+				format:        "sku,array",
+				isPointerType: true,
+			},
+			want: &[]interface{}{testSKU("SKU-1")},
+		},
+		{
+			name: "test_global_money",
+			args: args{
+				value:  "10.5",
+				format: "test_money",
+			},
+			want: testMoney{Cents: 1050},
+		},
+		{
+			name: "test_unknown",
+			args: args{
+				value:  "10.5",
+				format: "unknown",
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			csv := CSVParser{Comma: ',', Comment: '#', TrimLeadingSpace: true}
+			csv.RegisterType("sku", parseTestSKU)
+
+			rslt, err := csv.toTyped(tt.args.value, tt.args.format, tt.args.isPointerType)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("TestRegisterType() received error = %v", err)
+			}
+
+			if !reflect.DeepEqual(rslt, tt.want) {
+				t.Errorf("TestRegisterType() is not equal. \ngot = %+#v\nwant = %+#v", rslt, tt.want)
+			}
+		})
+	}
+}
+
+func TestCSV_RegisterType_Unmarshal(t *testing.T) {
+	csv := CSVParser{Comma: ',', Comment: '#', TrimLeadingSpace: true}
+	csv.RegisterType("sku", parseTestSKU)
+
+	type product struct {
+		SKU     testSKU   `csv:"sku"`
+		Related []testSKU `csv:"related"`
+	}
+
+	var rslt []product
+	err := csv.Unmarshal([]byte("sku,related\nsku,\"sku,array\"\nSKU-1,\"SKU-2,SKU-3\""), &rslt)
+	if err != nil {
+		t.Fatalf("TestRegisterTypeUnmarshal() received error = %v", err)
+	}
+
+	want := []product{{SKU: "SKU-1", Related: []testSKU{"SKU-2", "SKU-3"}}}
+	if !reflect.DeepEqual(rslt, want) {
+		t.Errorf("TestRegisterTypeUnmarshal() is not equal. \ngot = %+#v\nwant = %+#v", rslt, want)
+	}
+}
+
+// TestCSV_RegisterType_copy registers types on copies of a parser that is in use, run with -race to detect data races.
+func TestCSV_RegisterType_copy(t *testing.T) {
+	base := New(WithType("sku", parseTestSKU))
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func(register bool) {
+			defer wg.Done()
+
+			if register {
+				derived := *base
+				derived.RegisterType("money", parseTestMoney)
+
+				if _, err := derived.Typed([]byte("price,sku\nmoney,sku\n1.5,SKU-1")); err != nil {
+					t.Errorf("TestRegisterTypeCopy() received error = %v", err)
+				}
+				return
+			}
+
+			if _, err := base.Typed([]byte("sku\nsku\nSKU-1")); err != nil {
+				t.Errorf("TestRegisterTypeCopy() received error = %v", err)
+			}
+		}(i%2 == 0)
+	}
+	wg.Wait()
+
+	if _, ok := base.lookupType("money"); ok {
+		t.Errorf("TestRegisterTypeCopy() registered the type on the original parser")
+	}
+}
+
+func TestRegisterType_invalid(t *testing.T) {
+	tests := []struct {
+		name     string
+		typeName string
+		fn       TypeFunc
+	}{
+		{name: "test_nil_func", typeName: "sku"},
+		{name: "test_empty_name", typeName: "", fn: parseTestSKU},
+		{name: "test_pointer_name", typeName: "*sku", fn: parseTestSKU},
+		{name: "test_array_name", typeName: "sku,array", fn: parseTestSKU},
+		{name: "test_builtin_name", typeName: "int64", fn: parseTestSKU},
+		{name: "test_builtin_json", typeName: "json", fn: parseTestSKU},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("TestRegisterTypeInvalid() did not panic")
+				}
+			}()
+
+			RegisterType(tt.typeName, tt.fn)
+		})
+	}
+}
@@ -0,0 +1,300 @@
+package csvx
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+var (
+	ErrInvalidUnmarshalTarget = errors.New("unmarshal target must be a pointer to a slice of structs or struct pointers")
+	ErrTypeMismatch           = errors.New("csv type does not match go type")
+)
+
+// structColumn describes an exported struct field and its csv name.
+type structColumn struct {
+	name  string
+	index []int
+	typ   reflect.Type
+	// format is the type declared in the tag after the name, if any.
+	format string
+}
+
+// structField maps a csv column to a field of the target struct.
+type structField struct {
+	index  []int
+	column int
+	// columns contains all columns with the name of the field in the order of the header,
+	// if their values are collected.
+	columns []int
+	field   field
+}
+
+// elementError reports the index of the slice element that could not be stored.
+type elementError struct {
+	index int
+	err   error
+}
+
+func (e *elementError) Error() string {
+	return e.err.Error()
+}
+
+func (e *elementError) Unwrap() error {
+	return e.err
+}
+
+// Unmarshal parses the typed csv data using the default settings and stores the rows in the slice pointed to by v.
+//
+// See CSVParser.Unmarshal for details.
+func Unmarshal(data []byte, v interface{}) error {
+	return (&CSVParser{}).Unmarshal(data, v)
+}
+
+// Unmarshal parses the typed csv data and stores the rows in the slice pointed to by v.
+//
+// v must be a pointer to a slice of structs or struct pointers. The header names are matched against the
+// `csv:"name"` tags of the struct fields, fields without a tag are matched by their name and fields
+// tagged with `csv:"-"` are ignored. Columns without a matching field are skipped. A type declared in the tag
+// after the name, like `csv:"status,enum(draft|published)"`, is only used by Marshal.
+//
+// The types in the type row must match the go types of the fields, otherwise ErrTypeMismatch is returned.
+// Pointer types like "*int64" may be stored in an int64 field and vice versa. Cells of type json are
+// unmarshalled into the field with encoding/json (stdlib), so the field may have any type.
+func (c *CSVParser) Unmarshal(data []byte, v interface{}) error {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() || rv.Elem().Kind() != reflect.Slice {
+		return ErrInvalidUnmarshalTarget
+	}
+
+	slice := rv.Elem()
+	elemType := slice.Type().Elem()
+	structType := elemType
+	if elemType.Kind() == reflect.Ptr {
+		structType = elemType.Elem()
+	}
+	if structType.Kind() != reflect.Struct {
+		return ErrInvalidUnmarshalTarget
+	}
+
+	dec := c.TypedDecoder(bytes.NewReader(data))
+	if !dec.ensureHeader() {
+		return dec.Err()
+	}
+
+	fields, err := dec.parser.mapStructFields(structType, dec.headerInfo)
+	if err != nil {
+		return err
+	}
+
+	rslt := reflect.MakeSlice(slice.Type(), 0, 0)
+	for dec.Next() {
+		elem := reflect.New(structType)
+		for name, sf := range fields {
+			value, ok := dec.OrderedRow().Get(name)
+			if !ok {
+				continue
+			}
+
+			err := assignValue(elem.Elem().FieldByIndex(sf.index), value, strings.TrimPrefix(sf.field.Type, "*"))
+			if err != nil {
+				column := sf.column
+
+				// the collected values contain the cells of the row in the order of the columns
+				var elemErr *elementError
+				if sf.field.Collect && errors.As(err, &elemErr) && elemErr.index < len(sf.columns) {
+					column, err = sf.columns[elemErr.index], elemErr.err
+				}
+
+				raw := ""
+				if column < len(dec.record) {
+					raw = dec.record[column]
+				}
+
+				return &ParseError{
+					Line:   dec.Line(),
+					Column: column + 1,
+					Name:   name,
+					Type:   dec.headerInfo[column].Type,
+					Value:  raw,
+					Err:    err,
+				}
+			}
+		}
+
+		if elemType.Kind() == reflect.Ptr {
+			rslt = reflect.Append(rslt, elem)
+		} else {
+			rslt = reflect.Append(rslt, elem.Elem())
+		}
+	}
+	if err := dec.Err(); err != nil {
+		return err
+	}
+
+	slice.Set(rslt)
+	return nil
+}
+
+// mapStructFields matches the header fields against the fields of the struct type and checks that the types are compatible.
+func (c *CSVParser) mapStructFields(structType reflect.Type, headerInfo map[int]field) (map[string]structField, error) {
+	byName := map[string][]int{}
+	for _, column := range collectStructFields(structType, nil) {
+		if _, exists := byName[column.name]; !exists {
+			byName[column.name] = column.index
+		}
+	}
+
+	fields := map[string]structField{}
+	for idx := 0; idx < len(headerInfo); idx++ {
+		hf := headerInfo[idx]
+		index, ok := byName[hf.Name]
+		if !ok {
+			continue
+		}
+
+		if c.isTyped && hf.Type == "" && c.SkipEmptyColumns {
+			// skipped columns are not part of the rows
+			continue
+		}
+
+		format := strings.TrimPrefix(hf.Type, "*")
+		if format == "" {
+			// untyped columns are returned as string
+			format = "string"
+		}
+
+		fieldType := structType.FieldByIndex(index).Type
+		goType, ok := formatTypes[baseFormat(format)]
+		if ok && hf.Collect {
+			// the values of duplicate columns are collected into a slice
+			goType = reflect.SliceOf(goType)
+		}
+		if ok && !isAssignable(goType, fieldType) {
+			return nil, fmt.Errorf("%w: column %q of type %q cannot be stored in field of type %s", ErrTypeMismatch, hf.Name, hf.Type, fieldType)
+		}
+
+		var columns []int
+		if hf.Collect {
+			columns = append(fields[hf.Name].columns, idx)
+		}
+
+		fields[hf.Name] = structField{
+			index:   index,
+			column:  idx,
+			columns: columns,
+			field:   hf,
+		}
+	}
+
+	return fields, nil
+}
+
+// collectStructFields collects the exported fields of the struct type with their csv name in declaration order.
+// Fields of embedded structs are collected as if they were part of the outer struct.
+func collectStructFields(structType reflect.Type, parent []int) []structColumn {
+	columns := []structColumn{}
+	for i := 0; i < structType.NumField(); i++ {
+		sf := structType.Field(i)
+		index := append(append([]int{}, parent...), i)
+
+		if sf.Anonymous && sf.Type.Kind() == reflect.Struct {
+			columns = append(columns, collectStructFields(sf.Type, index)...)
+			continue
+		}
+
+		if sf.PkgPath != "" {
+			// unexported field
+			continue
+		}
+
+		name, format := sf.Tag.Get("csv"), ""
+		if idx := strings.IndexByte(name, ','); idx >= 0 {
+			name, format = name[:idx], name[idx+1:]
+		}
+		if name == "-" {
+			continue
+		}
+		if name == "" {
+			name = sf.Name
+		}
+
+		columns = append(columns, structColumn{
+			name:   name,
+			index:  index,
+			typ:    sf.Type,
+			format: format,
+		})
+	}
+
+	return columns
+}
+
+// isAssignable checks whether a value of type src can be stored in dst, allowing one level of pointer difference.
+func isAssignable(src, dst reflect.Type) bool {
+	switch {
+	case src.AssignableTo(dst):
+		return true
+	case dst.Kind() == reflect.Ptr:
+		return src.AssignableTo(dst.Elem())
+	default:
+		return false
+	}
+}
+
+// assignValue stores the converted value in dst.
+func assignValue(dst reflect.Value, value interface{}, format string) error {
+	if value == nil {
+		dst.Set(reflect.Zero(dst.Type()))
+		return nil
+	}
+
+	src := reflect.ValueOf(value)
+	if src.Kind() == reflect.Ptr {
+		if src.IsNil() {
+			dst.Set(reflect.Zero(dst.Type()))
+			return nil
+		}
+
+		if !src.Type().AssignableTo(dst.Type()) {
+			src = src.Elem()
+		}
+	}
+
+	switch {
+	case src.Type().AssignableTo(dst.Type()):
+		dst.Set(src)
+	case dst.Kind() == reflect.Ptr && src.Type().AssignableTo(dst.Type().Elem()):
+		p := reflect.New(dst.Type().Elem())
+		p.Elem().Set(src)
+		dst.Set(p)
+	case src.Kind() == reflect.Slice && src.Type().Elem().Kind() == reflect.Interface && dst.Kind() == reflect.Slice:
+		// arrays of custom types contain the elements as interface{}
+		values := reflect.MakeSlice(dst.Type(), src.Len(), src.Len())
+		for i := 0; i < src.Len(); i++ {
+			err := assignValue(values.Index(i), src.Index(i).Interface(), format)
+			if err != nil {
+				return &elementError{index: i, err: err}
+			}
+		}
+		dst.Set(values)
+	case format == "json":
+		// convert the generic json value into the type of the field
+		data, err := json.Marshal(src.Interface())
+		if err != nil {
+			return fmt.Errorf("%w: %s", ErrInEmbeddedJSON, err)
+		}
+
+		err = json.Unmarshal(data, dst.Addr().Interface())
+		if err != nil {
+			return fmt.Errorf("%w: %s", ErrInEmbeddedJSON, err)
+		}
+	default:
+		return fmt.Errorf("%w: cannot store %s in field of type %s", ErrTypeMismatch, src.Type(), dst.Type())
+	}
+
+	return nil
+}
@@ -0,0 +1,148 @@
+package csvx
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+type unmarshalAddress struct {
+	Street string `json:"street"`
+	Zip    int    `json:"zip"`
+}
+
+type unmarshalBase struct {
+	ID int64 `csv:"id"`
+}
+
+type unmarshalTarget struct {
+	unmarshalBase
+	Name    string           `csv:"name"`
+	Counter *int             `csv:"counter"`
+	Score   float64          `csv:"score"`
+	Active  bool             `csv:"active"`
+	Tags    []string         `csv:"tags"`
+	Address unmarshalAddress `csv:"address"`
+	Ignored string           `csv:"-"`
+	Note    string
+}
+
+func TestCSV_Unmarshal(t *testing.T) {
+	type args struct {
+		data []byte
+	}
+	tests := []struct {
+		name    string
+		args    args
+		want    []unmarshalTarget
+		wantErr error
+	}{
+		{
+			name: "test_unmarshal",
+			args: args{
+				data: []byte(`
+				id,name,counter,score,active,tags,address,Note,-
+				int64,string,*int,*float64,bool,"string,array",json,string,string
+				1,first,10,1.5,true,"a,b","{""street"": ""main"", ""zip"": 12345}",hello,ignored
+				2,second,,,false,,,,`),
+			},
+			want: []unmarshalTarget{
+				{
+					unmarshalBase: unmarshalBase{ID: 1},
+					Name:          "first",
+					Counter:       func(i int) *int { return &i }(10),
+					Score:         1.5,
+					Active:        true,
+					Tags:          []string{"a", "b"},
+					Address:       unmarshalAddress{Street: "main", Zip: 12345},
+					Note:          "hello",
+				},
+				{
+					unmarshalBase: unmarshalBase{ID: 2},
+					Name:          "second",
+					Tags:          []string{},
+				},
+			},
+		},
+		{
+			name: "test_unmarshal_unknown_column",
+			args: args{
+				data: []byte(`
+				id,unknown
+				int64,string
+				1,value`),
+			},
+			want: []unmarshalTarget{
+				{unmarshalBase: unmarshalBase{ID: 1}},
+			},
+		},
+		{
+			name: "test_unmarshal_type_mismatch",
+			args: args{
+				data: []byte(`
+				id,name
+				int64,int64
+				1,2`),
+			},
+			wantErr: ErrTypeMismatch,
+		},
+		{
+			name: "test_unmarshal_invalid_value",
+			args: args{
+				data: []byte(`
+				id,address
+				int64,json
+				1,{"street": 10}`),
+			},
+			wantErr: ErrInEmbeddedJSON,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			csv := CSVParser{Comma: ',', Comment: '#', TrimLeadingSpace: true}
+
+			var rslt []unmarshalTarget
+			err := csv.Unmarshal(tt.args.data, &rslt)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("TestUnmarshal() received error = %v, want %v", err, tt.wantErr)
+			}
+
+			if !reflect.DeepEqual(rslt, tt.want) {
+				t.Errorf("TestUnmarshal() is not equal. \ngot = %+#v\nwant = %+#v", rslt, tt.want)
+			}
+		})
+	}
+}
+
+func TestUnmarshal_pointerSlice(t *testing.T) {
+	var rslt []*unmarshalBase
+	err := Unmarshal([]byte("id\nint64\n1\n2"), &rslt)
+	if err != nil {
+		t.Fatalf("TestUnmarshalPointerSlice() received error = %v", err)
+	}
+
+	want := []*unmarshalBase{{ID: 1}, {ID: 2}}
+	if !reflect.DeepEqual(rslt, want) {
+		t.Errorf("TestUnmarshalPointerSlice() is not equal. \ngot = %+#v\nwant = %+#v", rslt, want)
+	}
+}
+
+func TestUnmarshal_invalidTarget(t *testing.T) {
+	tests := []struct {
+		name   string
+		target interface{}
+	}{
+		{name: "test_nil", target: nil},
+		{name: "test_no_pointer", target: []unmarshalBase{}},
+		{name: "test_no_slice", target: &unmarshalBase{}},
+		{name: "test_no_struct", target: &[]string{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Unmarshal([]byte("id\nint64\n1"), tt.target)
+			if !errors.Is(err, ErrInvalidUnmarshalTarget) {
+				t.Errorf("TestUnmarshalInvalidTarget() received error = %v", err)
+			}
+		})
+	}
+}
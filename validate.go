@@ -0,0 +1,26 @@
+package csvx
+
+import (
+	"bytes"
+)
+
+// Validate parses the typed data like Typed, but does not stop at the first cell that cannot be converted.
+//
+// All rows are read and every cell is converted. The rows without any invalid cell are returned together
+// with a ParseErrors error that lists every invalid cell. If all cells are valid, the error is nil.
+// Errors that prevent reading the csv at all, like a missing type row, are returned without any rows.
+func (c *CSVParser) Validate(data []byte) ([]map[string]interface{}, error) {
+	dec := c.TypedDecoder(bytes.NewReader(data))
+	dec.collectErrors = true
+
+	rslt, err := c.decodeAll(dec)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(dec.errs) > 0 {
+		return rslt, dec.errs
+	}
+
+	return rslt, nil
+}
@@ -0,0 +1,107 @@
+package csvx
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestCSV_Validate(t *testing.T) {
+	type args struct {
+		data []byte
+	}
+	tests := []struct {
+		name       string
+		args       args
+		want       []map[string]interface{}
+		wantErrors []*ParseError
+		wantErr    error
+	}{
+		{
+			name: "test_valid",
+			args: args{
+				data: []byte("foo,bar\nstring,int64\nfirst,10\nsecond,20"),
+			},
+			want: []map[string]interface{}{
+				{
+					"foo": "first",
+					"bar": int64(10),
+				},
+				{
+					"foo": "second",
+					"bar": int64(20),
+				},
+			},
+		},
+		{
+			name: "test_collect_all_errors",
+			args: args{
+				data: []byte("foo,bar,baz\nint,int64,bool\n1,abc,true\n2,20,false\nthree,30,maybe\n4,40,true"),
+			},
+			want: []map[string]interface{}{
+				{
+					"foo": 2,
+					"bar": int64(20),
+					"baz": false,
+				},
+				{
+					"foo": 4,
+					"bar": int64(40),
+					"baz": true,
+				},
+			},
+			wantErrors: []*ParseError{
+				{Line: 3, Column: 2, Name: "bar", Type: "int64", Value: "abc"},
+				{Line: 5, Column: 1, Name: "foo", Type: "int", Value: "three"},
+				{Line: 5, Column: 3, Name: "baz", Type: "bool", Value: "maybe"},
+			},
+		},
+		{
+			name: "test_missing_type_row",
+			args: args{
+				data: []byte("foo,bar"),
+			},
+			wantErr: ErrDataIsNil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			csv := CSVParser{Comma: ',', Comment: '#'}
+
+			rslt, err := csv.Validate(tt.args.data)
+
+			var errs ParseErrors
+			if errors.As(err, &errs) {
+				for idx := range errs {
+					if idx < len(tt.wantErrors) {
+						tt.wantErrors[idx].Err = errs[idx].Err
+					}
+				}
+
+				if !reflect.DeepEqual([]*ParseError(errs), tt.wantErrors) {
+					t.Errorf("TestValidate() errors are not equal. \ngot = %+v\nwant = %+v", errs, tt.wantErrors)
+				}
+			} else if !errors.Is(err, tt.wantErr) || tt.wantErrors != nil {
+				t.Errorf("TestValidate() received error = %v, want %v", err, tt.wantErr)
+			}
+
+			if !reflect.DeepEqual(rslt, tt.want) {
+				t.Errorf("TestValidate() is not equal. \ngot = %+#v\nwant = %+#v", rslt, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseErrors_Error(t *testing.T) {
+	errs := ParseErrors{
+		{Line: 3, Column: 2, Name: "bar", Type: "int64", Value: "abc", Err: ErrUnsupportedType},
+		{Line: 5, Column: 1, Name: "foo", Type: "int", Value: "three", Err: ErrUnsupportedType},
+	}
+
+	want := "2 invalid values:\n" +
+		`line 3, column 2 ("bar" of type "int64"): invalid value "abc": unsupported type format type` + "\n" +
+		`line 5, column 1 ("foo" of type "int"): invalid value "three": unsupported type format type`
+	if errs.Error() != want {
+		t.Errorf("TestParseErrorsError() is not equal. \ngot = %s\nwant = %s", errs.Error(), want)
+	}
+}